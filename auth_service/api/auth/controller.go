@@ -6,6 +6,7 @@ import (
 	"github.com/afteracademy/gomicro/auth-service/api/user"
 	"github.com/afteracademy/gomicro/auth-service/common"
 	"github.com/afteracademy/gomicro/auth-service/utils"
+	authv1 "github.com/afteracademy/gomicro/contracts/auth/v1"
 	"github.com/afteracademy/goserve/v2/micro"
 	"github.com/afteracademy/goserve/v2/network"
 	"github.com/gin-gonic/gin"
@@ -14,6 +15,7 @@ import (
 type controller struct {
 	micro.Controller
 	common.ContextPayload
+	identity    common.ServiceIdentity
 	service     Service
 	userService user.Service
 }
@@ -21,30 +23,35 @@ type controller struct {
 func NewController(
 	authProvider network.AuthenticationProvider,
 	authorizeProvider network.AuthorizationProvider,
+	identity common.ServiceIdentity,
 	service Service,
 	userService user.Service,
 ) micro.Controller {
 	return &controller{
 		Controller:     micro.NewController("/", authProvider, authorizeProvider),
 		ContextPayload: common.NewContextPayload(),
+		identity:       identity,
 		service:        service,
 		userService:    userService,
 	}
 }
 
 func (c *controller) MountNats(group micro.NatsGroup) {
-	group.AddEndpoint("authentication", micro.NatsHandlerFunc(c.authenticationHandler))
-	group.AddEndpoint("authorization", micro.NatsHandlerFunc(c.authorizationHandler))
+	group.AddEndpoint(authv1.NATS_ENDPOINT_AUTH, c.identity.Guard(c.authenticationHandler))
+	group.AddEndpoint(authv1.NATS_ENDPOINT_AUTHZ, c.identity.Guard(c.authorizationHandler))
 }
 
 func (c *controller) authenticationHandler(req micro.NatsRequest) {
+	ctx, cancel := common.NatsContext(req)
+	defer cancel()
+
 	text, err := micro.JsonToMsg[message.Text](req.Data())
 	if err != nil {
 		micro.RespondNatsError(req, err)
 		return
 	}
 
-	user, _, err := c.service.Authenticate(text.Value)
+	user, _, err := c.service.Authenticate(ctx, text.Value)
 	if err != nil {
 		micro.RespondNatsError(req, err)
 		return
@@ -54,13 +61,16 @@ func (c *controller) authenticationHandler(req micro.NatsRequest) {
 }
 
 func (c *controller) authorizationHandler(req micro.NatsRequest) {
+	ctx, cancel := common.NatsContext(req)
+	defer cancel()
+
 	userRole, err := micro.JsonToMsg[message.UserRole](req.Data())
 	if err != nil {
 		micro.RespondNatsError(req, err)
 		return
 	}
 
-	user, err := c.userService.FetchUserById(userRole.User.ID)
+	user, err := c.userService.FetchUserById(ctx, userRole.User.ID)
 	if err != nil {
 		micro.RespondNatsError(req, err)
 		return
@@ -90,7 +100,7 @@ func (c *controller) verifyApikeyHandler(ctx *gin.Context) {
 		return
 	}
 
-	_, err := c.service.FetchApiKey(key)
+	_, err := c.service.FetchApiKey(ctx.Request.Context(), key)
 	if err != nil {
 		network.SendForbiddenError(ctx, "permission denied: invalid x-api-key", err)
 		return
@@ -106,7 +116,7 @@ func (c *controller) signUpBasicHandler(ctx *gin.Context) {
 		return
 	}
 
-	data, err := c.service.SignUpBasic(body)
+	data, err := c.service.SignUpBasic(ctx.Request.Context(), body)
 	if err != nil {
 		network.SendMixedError(ctx, err)
 		return
@@ -122,7 +132,7 @@ func (c *controller) signInBasicHandler(ctx *gin.Context) {
 		return
 	}
 
-	dto, err := c.service.SignInBasic(body)
+	dto, err := c.service.SignInBasic(ctx.Request.Context(), body)
 	if err != nil {
 		network.SendMixedError(ctx, err)
 		return
@@ -134,7 +144,7 @@ func (c *controller) signInBasicHandler(ctx *gin.Context) {
 func (c *controller) signOutBasic(ctx *gin.Context) {
 	keystore := c.MustGetKeystore(ctx)
 
-	err := c.service.SignOut(keystore)
+	err := c.service.SignOut(ctx.Request.Context(), keystore)
 	if err != nil {
 		network.SendInternalServerError(ctx, "something went wrong", err)
 		return
@@ -153,7 +163,7 @@ func (c *controller) tokenRefreshHandler(ctx *gin.Context) {
 	authHeader := ctx.GetHeader(network.AuthorizationHeader)
 	accessToken := utils.ExtractBearerToken(authHeader)
 
-	dto, err := c.service.RenewToken(body, accessToken)
+	dto, err := c.service.RenewToken(ctx.Request.Context(), body, accessToken)
 	if err != nil {
 		network.SendMixedError(ctx, err)
 		return
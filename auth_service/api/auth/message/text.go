@@ -1,11 +1,11 @@
 package message
 
-type Text struct {
-	Value string `json:"value" validate:"required"`
-}
+import (
+	authv1 "github.com/afteracademy/gomicro/contracts/auth/v1"
+)
+
+type Text = authv1.Text
 
 func NewText(value string) *Text {
-	return &Text{
-		Value: value,
-	}
+	return authv1.NewText(value)
 }
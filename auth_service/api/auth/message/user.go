@@ -2,15 +2,10 @@ package message
 
 import (
 	"github.com/afteracademy/gomicro/auth-service/api/user/model"
-	"github.com/google/uuid"
+	authv1 "github.com/afteracademy/gomicro/contracts/auth/v1"
 )
 
-type User struct {
-	ID            uuid.UUID `json:"id" validate:"required,uuid"`
-	Name          string    `json:"name" validate:"required"`
-	Email         string    `json:"email" validate:"required,email"`
-	ProfilePicURL *string   `json:"profilePicUrl,omitempty" validate:"omitempty,url"`
-}
+type User = authv1.User
 
 func NewUser(user *model.User) *User {
 	return &User{
@@ -20,3 +15,17 @@ func NewUser(user *model.User) *User {
 		ProfilePicURL: user.ProfilePicURL,
 	}
 }
+
+type UserIds = authv1.UserIds
+
+type Users = authv1.Users
+
+func NewUsers(users []*model.User) *Users {
+	msgs := make([]*User, len(users))
+	for i, u := range users {
+		msgs[i] = NewUser(u)
+	}
+	return &Users{
+		Users: msgs,
+	}
+}
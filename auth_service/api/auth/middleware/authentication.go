@@ -26,7 +26,7 @@ func (m *authenticationProvider) Middleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader(network.AuthorizationHeader)
 
-		user, keystore, err := m.authService.Authenticate(authHeader)
+		user, keystore, err := m.authService.Authenticate(ctx.Request.Context(), authHeader)
 		if err != nil {
 			network.SendMixedError(ctx, err)
 			return
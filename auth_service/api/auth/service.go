@@ -20,24 +20,24 @@ import (
 )
 
 type Service interface {
-	Authenticate(token string) (*userModel.User, *model.Keystore, error)
+	Authenticate(ctx context.Context, token string) (*userModel.User, *model.Keystore, error)
 	Authorize(user *userModel.User, roles ...string) error
-	SignUpBasic(signUpDto *dto.SignUpBasic) (*dto.UserAuth, error)
-	SignInBasic(signInDto *dto.SignInBasic) (*dto.UserAuth, error)
-	RenewToken(tokenRefreshDto *dto.TokenRefresh, accessToken string) (*dto.Tokens, error)
-	SignOut(keystore *model.Keystore) error
-	IsEmailRegisted(email string) bool
-	GenerateToken(user *userModel.User) (string, string, error)
-	FetchKeystore(client *userModel.User, primaryKey string) (*model.Keystore, error)
+	SignUpBasic(ctx context.Context, signUpDto *dto.SignUpBasic) (*dto.UserAuth, error)
+	SignInBasic(ctx context.Context, signInDto *dto.SignInBasic) (*dto.UserAuth, error)
+	RenewToken(ctx context.Context, tokenRefreshDto *dto.TokenRefresh, accessToken string) (*dto.Tokens, error)
+	SignOut(ctx context.Context, keystore *model.Keystore) error
+	IsEmailRegisted(ctx context.Context, email string) bool
+	GenerateToken(ctx context.Context, user *userModel.User) (string, string, error)
+	FetchKeystore(ctx context.Context, client *userModel.User, primaryKey string) (*model.Keystore, error)
 	VerifyToken(tokenStr string) (*jwt.RegisteredClaims, error)
 	DecodeToken(tokenStr string) (*jwt.RegisteredClaims, error)
 	SignToken(claims jwt.RegisteredClaims) (string, error)
 	ValidateClaims(claims *jwt.RegisteredClaims) bool
-	FetchApiKey(key string) (*model.ApiKey, error)
+	FetchApiKey(ctx context.Context, key string) (*model.ApiKey, error)
 
 	/*--------only for tests----------*/
-	CreateApiKey(key string, version int, permissions []model.Permission, comments []string) (*model.ApiKey, error)
-	DeleteApiKey(apikey *model.ApiKey) (bool, error)
+	CreateApiKey(ctx context.Context, key string, version int, permissions []model.Permission, comments []string) (*model.ApiKey, error)
+	DeleteApiKey(ctx context.Context, apikey *model.ApiKey) (bool, error)
 	/*--------------------------------*/
 }
 
@@ -91,7 +91,7 @@ func NewService(
 	}
 }
 
-func (s *service) Authenticate(authToken string) (*userModel.User, *model.Keystore, error) {
+func (s *service) Authenticate(ctx context.Context, authToken string) (*userModel.User, *model.Keystore, error) {
 	if len(authToken) == 0 {
 		return nil, nil, network.NewUnauthorizedError("permission denied: missing Authorization", nil)
 	}
@@ -116,12 +116,12 @@ func (s *service) Authenticate(authToken string) (*userModel.User, *model.Keysto
 		return nil, nil, network.NewUnauthorizedError("permission denied: invalid claims subject", nil)
 	}
 
-	user, err := s.userService.FetchUserById(userId)
+	user, err := s.userService.FetchUserById(ctx, userId)
 	if err != nil {
 		return nil, nil, network.NewUnauthorizedError("permission denied: claims subject does not exists", err)
 	}
 
-	keystore, err := s.FetchKeystore(user, claims.ID)
+	keystore, err := s.FetchKeystore(ctx, user, claims.ID)
 	if err != nil || keystore == nil {
 		return nil, nil, network.NewUnauthorizedError("permission denied: invalid access token", err)
 	}
@@ -153,13 +153,13 @@ func (s *service) Authorize(user *userModel.User, roleNames ...string) error {
 	return nil
 }
 
-func (s *service) SignUpBasic(signUpDto *dto.SignUpBasic) (*dto.UserAuth, error) {
-	exists := s.IsEmailRegisted(signUpDto.Email)
+func (s *service) SignUpBasic(ctx context.Context, signUpDto *dto.SignUpBasic) (*dto.UserAuth, error) {
+	exists := s.IsEmailRegisted(ctx, signUpDto.Email)
 	if exists {
 		return nil, network.NewBadRequestError("user already registered", nil)
 	}
 
-	role, err := s.userService.FetchRoleByCode(userModel.RoleCodeLearner)
+	role, err := s.userService.FetchRoleByCode(ctx, userModel.RoleCodeLearner)
 	if err != nil {
 		return nil, err
 	}
@@ -171,12 +171,12 @@ func (s *service) SignUpBasic(signUpDto *dto.SignUpBasic) (*dto.UserAuth, error)
 		return nil, err
 	}
 
-	user, err := s.userService.CreateUser(signUpDto.Email, string(hashed), signUpDto.Name, signUpDto.ProfilePicUrl, roles)
+	user, err := s.userService.CreateUser(ctx, signUpDto.Email, string(hashed), signUpDto.Name, signUpDto.ProfilePicUrl, roles)
 	if err != nil {
 		return nil, err
 	}
 
-	accessToken, refreshToken, err := s.GenerateToken(user)
+	accessToken, refreshToken, err := s.GenerateToken(ctx, user)
 	if err != nil {
 		return nil, err
 	}
@@ -185,8 +185,8 @@ func (s *service) SignUpBasic(signUpDto *dto.SignUpBasic) (*dto.UserAuth, error)
 	return dto.NewUserAuth(user, tokens), nil
 }
 
-func (s *service) SignInBasic(signInDto *dto.SignInBasic) (*dto.UserAuth, error) {
-	user, err := s.userService.FetchUserByEmail(signInDto.Email)
+func (s *service) SignInBasic(ctx context.Context, signInDto *dto.SignInBasic) (*dto.UserAuth, error) {
+	user, err := s.userService.FetchUserByEmail(ctx, signInDto.Email)
 	if err != nil {
 		return nil, network.NewNotFoundError("user not registerd", err)
 	}
@@ -196,7 +196,7 @@ func (s *service) SignInBasic(signInDto *dto.SignInBasic) (*dto.UserAuth, error)
 		return nil, network.NewUnauthorizedError("wrong password", err)
 	}
 
-	accessToken, refreshToken, err := s.GenerateToken(user)
+	accessToken, refreshToken, err := s.GenerateToken(ctx, user)
 	if err != nil {
 		return nil, err
 	}
@@ -205,9 +205,7 @@ func (s *service) SignInBasic(signInDto *dto.SignInBasic) (*dto.UserAuth, error)
 	return dto.NewUserAuth(user, tokens), nil
 }
 
-func (s *service) SignOut(keystore *model.Keystore) error {
-	ctx := context.Background()
-
+func (s *service) SignOut(ctx context.Context, keystore *model.Keystore) error {
 	query := `
 		DELETE FROM keystore
 		WHERE id = $1
@@ -217,14 +215,12 @@ func (s *service) SignOut(keystore *model.Keystore) error {
 	return err
 }
 
-func (s *service) IsEmailRegisted(email string) bool {
-	exists, _ := s.userService.IsEmailExists(email)
+func (s *service) IsEmailRegisted(ctx context.Context, email string) bool {
+	exists, _ := s.userService.IsEmailExists(ctx, email)
 	return exists
 }
 
-func (s *service) RenewToken(tokenRefreshDto *dto.TokenRefresh, accessToken string) (*dto.Tokens, error) {
-	ctx := context.Background()
-
+func (s *service) RenewToken(ctx context.Context, tokenRefreshDto *dto.TokenRefresh, accessToken string) (*dto.Tokens, error) {
 	accessClaims, err := s.DecodeToken(accessToken)
 	if err != nil {
 		return nil, err
@@ -250,7 +246,7 @@ func (s *service) RenewToken(tokenRefreshDto *dto.TokenRefresh, accessToken stri
 	}
 
 	userId, _ := uuid.Parse(refreshClaims.Subject)
-	user, err := s.userService.FetchUserById(userId)
+	user, err := s.userService.FetchUserById(ctx, userId)
 	if err != nil {
 		return nil, network.NewUnauthorizedError("permission denied: invalid refresh claims subject", nil)
 	}
@@ -260,12 +256,12 @@ func (s *service) RenewToken(tokenRefreshDto *dto.TokenRefresh, accessToken stri
 		return nil, network.NewUnauthorizedError("permission denied: claims ids", nil)
 	}
 
-	err = s.SignOut(keystore)
+	err = s.SignOut(ctx, keystore)
 	if err != nil {
 		return nil, nil
 	}
 
-	accessToken, refreshToken, err := s.GenerateToken(user)
+	accessToken, refreshToken, err := s.GenerateToken(ctx, user)
 	if err != nil {
 		return nil, err
 	}
@@ -273,8 +269,7 @@ func (s *service) RenewToken(tokenRefreshDto *dto.TokenRefresh, accessToken stri
 	return dto.NewTokens(accessToken, refreshToken), nil
 }
 
-func (s *service) GenerateToken(user *userModel.User) (string, string, error) {
-	ctx := context.Background()
+func (s *service) GenerateToken(ctx context.Context, user *userModel.User) (string, string, error) {
 	primaryKey, err := utility.GenerateRandomString(32)
 	if err != nil {
 		return "", "", err
@@ -325,11 +320,12 @@ func (s *service) GenerateToken(user *userModel.User) (string, string, error) {
 }
 
 func (s *service) GenerateKeystore(
+	ctx context.Context,
 	client *userModel.User,
 	primaryKey string,
 	secondaryKey string,
 ) (*model.Keystore, error) {
-	return s.CreateKeystore(context.Background(), client, primaryKey, secondaryKey)
+	return s.CreateKeystore(ctx, client, primaryKey, secondaryKey)
 }
 
 func (s *service) CreateKeystore(
@@ -374,10 +370,10 @@ func (s *service) CreateKeystore(
 }
 
 func (s *service) FetchKeystore(
+	ctx context.Context,
 	client *userModel.User,
 	primaryKey string,
 ) (*model.Keystore, error) {
-	ctx := context.Background()
 	query := `
 		SELECT
 			id,
@@ -520,9 +516,9 @@ func (s *service) ValidateClaims(claims *jwt.RegisteredClaims) bool {
 }
 
 func (s *service) FetchApiKey(
+	ctx context.Context,
 	key string,
 ) (*model.ApiKey, error) {
-	ctx := context.Background()
 	query := `
 		SELECT
 			id,
@@ -560,12 +556,12 @@ func (s *service) FetchApiKey(
 }
 
 func (s *service) CreateApiKey(
+	ctx context.Context,
 	key string,
 	version int,
 	permissions []model.Permission,
 	comments []string,
 ) (*model.ApiKey, error) {
-	ctx := context.Background()
 	var apiKey model.ApiKey
 
 	query := `
@@ -613,9 +609,9 @@ func (s *service) CreateApiKey(
 }
 
 func (s *service) DeleteApiKey(
+	ctx context.Context,
 	apiKey *model.ApiKey,
 ) (bool, error) {
-	ctx := context.Background()
 	query := `
 		DELETE FROM api_keys
 		WHERE id = $1
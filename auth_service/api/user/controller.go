@@ -3,6 +3,7 @@ package user
 import (
 	"github.com/afteracademy/gomicro/auth-service/api/auth/message"
 	"github.com/afteracademy/gomicro/auth-service/common"
+	authv1 "github.com/afteracademy/gomicro/contracts/auth/v1"
 	coredto "github.com/afteracademy/goserve/v2/dto"
 	"github.com/afteracademy/goserve/v2/micro"
 	"github.com/afteracademy/goserve/v2/network"
@@ -13,26 +14,33 @@ import (
 type controller struct {
 	micro.Controller
 	common.ContextPayload
-	service Service
+	identity common.ServiceIdentity
+	service  Service
 }
 
 func NewController(
 	authProvider network.AuthenticationProvider,
 	authorizeProvider network.AuthorizationProvider,
+	identity common.ServiceIdentity,
 	service Service,
 ) micro.Controller {
 	return &controller{
 		Controller:     micro.NewController("/profile", authProvider, authorizeProvider),
 		ContextPayload: common.NewContextPayload(),
+		identity:       identity,
 		service:        service,
 	}
 }
 
 func (c *controller) MountNats(group micro.NatsGroup) {
-	group.AddEndpoint("user", micro.NatsHandlerFunc(c.userHandler))
+	group.AddEndpoint(authv1.NATS_ENDPOINT_USERPROFILE, c.identity.Guard(c.userHandler))
+	group.AddEndpoint(authv1.NATS_ENDPOINT_USERPROFILES, c.identity.Guard(c.usersHandler))
 }
 
 func (c *controller) userHandler(req micro.NatsRequest) {
+	ctx, cancel := common.NatsContext(req)
+	defer cancel()
+
 	text, err := micro.JsonToMsg[message.Text](req.Data())
 	if err != nil {
 		micro.RespondNatsError(req, err)
@@ -45,7 +53,7 @@ func (c *controller) userHandler(req micro.NatsRequest) {
 		return
 	}
 
-	user, err := c.service.FetchUserById(userId)
+	user, err := c.service.FetchUserById(ctx, userId)
 	if err != nil {
 		micro.RespondNatsError(req, err)
 		return
@@ -54,6 +62,30 @@ func (c *controller) userHandler(req micro.NatsRequest) {
 	micro.RespondNatsMessage(req, message.NewUser(user))
 }
 
+func (c *controller) usersHandler(req micro.NatsRequest) {
+	ctx, cancel := common.NatsContext(req)
+	defer cancel()
+
+	userIds, err := micro.JsonToMsg[message.UserIds](req.Data())
+	if err != nil {
+		micro.RespondNatsError(req, err)
+		return
+	}
+
+	if len(userIds.Ids) > authv1.MaxUserIds {
+		micro.RespondNatsError(req, network.NewBadRequestError("too many user ids", nil))
+		return
+	}
+
+	users, err := c.service.FindUserPublicProfiles(ctx, userIds.Ids)
+	if err != nil {
+		micro.RespondNatsError(req, err)
+		return
+	}
+
+	micro.RespondNatsMessage(req, message.NewUsers(users))
+}
+
 func (c *controller) MountRoutes(group *gin.RouterGroup) {
 	group.GET("/id/:id", c.getPublicProfileHandler)
 	private := group.Use(c.Authentication())
@@ -67,7 +99,7 @@ func (c *controller) getPublicProfileHandler(ctx *gin.Context) {
 		return
 	}
 
-	data, err := c.service.FetchUserPublicProfile(uuidParam.ID)
+	data, err := c.service.FetchUserPublicProfile(ctx.Request.Context(), uuidParam.ID)
 	if err != nil {
 		network.SendMixedError(ctx, err)
 		return
@@ -12,19 +12,20 @@ import (
 
 type Service interface {
 	FetchUserPrivateProfile(user *model.User) (*dto.UserPrivate, error)
-	FetchUserPublicProfile(userId uuid.UUID) (*dto.UserPublic, error)
-	FetchUserById(id uuid.UUID) (*model.User, error)
-	IsEmailExists(email string) (bool, error)
-	FetchUserByEmail(email string) (*model.User, error)
-	RemoveUserByEmail(email string) (bool, error)
-	FetchRoleByCode(code model.RoleCode) (*model.Role, error)
+	FetchUserPublicProfile(ctx context.Context, userId uuid.UUID) (*dto.UserPublic, error)
+	FetchUserById(ctx context.Context, id uuid.UUID) (*model.User, error)
+	FindUserPublicProfiles(ctx context.Context, userIDs []uuid.UUID) ([]*model.User, error)
+	IsEmailExists(ctx context.Context, email string) (bool, error)
+	FetchUserByEmail(ctx context.Context, email string) (*model.User, error)
+	RemoveUserByEmail(ctx context.Context, email string) (bool, error)
+	FetchRoleByCode(ctx context.Context, code model.RoleCode) (*model.Role, error)
 	CreateUser(
-		email string, password string, name string, profilePicURL *string, roles []*model.Role,
+		ctx context.Context, email string, password string, name string, profilePicURL *string, roles []*model.Role,
 	) (*model.User, error)
 
 	/*--------only for tests----------*/
-	CreateRole(code model.RoleCode) (*model.Role, error)
-	DeleteRole(role *model.Role) (bool, error)
+	CreateRole(ctx context.Context, code model.RoleCode) (*model.Role, error)
+	DeleteRole(ctx context.Context, role *model.Role) (bool, error)
 	/*--------------------------------*/
 }
 
@@ -43,35 +44,34 @@ func (s *service) FetchUserPrivateProfile(user *model.User) (*dto.UserPrivate, e
 	return dto.NewUserPrivate(user), nil
 }
 
-func (s *service) FetchUserPublicProfile(userId uuid.UUID) (*dto.UserPublic, error) {
-	user, err := s.FindUserPublicProfile(context.Background(), userId)
+func (s *service) FetchUserPublicProfile(ctx context.Context, userId uuid.UUID) (*dto.UserPublic, error) {
+	user, err := s.FindUserPublicProfile(ctx, userId)
 	if err != nil {
 		return nil, network.NewNotFoundError("user does not exists", err)
 	}
 	return dto.NewUserPublic(user), nil
 }
 
-func (s *service) FetchUserById(id uuid.UUID) (*model.User, error) {
-	return s.FindUserById(context.Background(), id)
+func (s *service) FetchUserById(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	return s.FindUserById(ctx, id)
 }
 
-func (s *service) FetchUserByEmail(email string) (*model.User, error) {
-	return s.FindUserByEmail(context.Background(), email)
+func (s *service) FetchUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	return s.FindUserByEmail(ctx, email)
 }
 
-func (s *service) RemoveUserByEmail(email string) (bool, error) {
-	return s.DeleteUserByEmail(context.Background(), email)
+func (s *service) RemoveUserByEmail(ctx context.Context, email string) (bool, error) {
+	return s.DeleteUserByEmail(ctx, email)
 }
 
-func (s *service) FetchRoleByCode(code model.RoleCode) (*model.Role, error) {
-	return s.FindRoleByCode(context.Background(), code)
+func (s *service) FetchRoleByCode(ctx context.Context, code model.RoleCode) (*model.Role, error) {
+	return s.FindRoleByCode(ctx, code)
 }
 
 func (s *service) IsEmailExists(
+	ctx context.Context,
 	email string,
 ) (bool, error) {
-	ctx := context.Background()
-
 	query := `
 		SELECT EXISTS (
 			SELECT 1
@@ -313,10 +313,8 @@ func (s *service) FindUserRoles(ctx context.Context, user model.User) ([]*model.
 }
 
 func (s *service) CreateUser(
-	email string, password string, name string, profilePicURL *string, roles []*model.Role,
+	ctx context.Context, email string, password string, name string, profilePicURL *string, roles []*model.Role,
 ) (*model.User, error) {
-	ctx := context.Background()
-
 	var user model.User
 
 	tx, err := s.db.Pool().Begin(ctx)
@@ -459,6 +457,48 @@ func (s *service) FindUserPublicProfile(
 	return &user, nil
 }
 
+func (s *service) FindUserPublicProfiles(
+	ctx context.Context,
+	userIDs []uuid.UUID,
+) ([]*model.User, error) {
+
+	if len(userIDs) == 0 {
+		return []*model.User{}, nil
+	}
+
+	query := `
+		SELECT
+			id,
+			name,
+			profile_pic_url
+		FROM users
+		WHERE id = ANY($1)
+		  AND status = TRUE
+	`
+
+	rows, err := s.db.Pool().Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []*model.User{}
+
+	for rows.Next() {
+		var user model.User
+		if err := rows.Scan(&user.ID, &user.Name, &user.ProfilePicURL); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
 func (s *service) DeleteUserByEmail(ctx context.Context, email string) (bool, error) {
 	query := `
 		DELETE FROM users
@@ -473,9 +513,7 @@ func (s *service) DeleteUserByEmail(ctx context.Context, email string) (bool, er
 	return tag.RowsAffected() > 0, nil
 }
 
-func (s *service) CreateRole(code model.RoleCode) (*model.Role, error) {
-	ctx := context.Background()
-
+func (s *service) CreateRole(ctx context.Context, code model.RoleCode) (*model.Role, error) {
 	var role model.Role
 
 	query := `
@@ -510,9 +548,7 @@ func (s *service) CreateRole(code model.RoleCode) (*model.Role, error) {
 	return &role, nil
 }
 
-func (s *service) DeleteRole(role *model.Role) (bool, error) {
-	ctx := context.Background()
-
+func (s *service) DeleteRole(ctx context.Context, role *model.Role) (bool, error) {
 	query := `
 		DELETE FROM roles
 		WHERE id = $1
@@ -0,0 +1,48 @@
+package common
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/gomicro/contracts/deadline"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+)
+
+type requestDeadline struct {
+	timeout time.Duration
+}
+
+// NewRequestDeadline bounds the request context, which is handed down to the
+// database calls, so that work is cancelled when the client disconnects or the
+// timeout passes.
+func NewRequestDeadline(timeout time.Duration) network.RootMiddleware {
+	return &requestDeadline{
+		timeout: timeout,
+	}
+}
+
+func (m *requestDeadline) Attach(engine *gin.Engine) {
+	engine.Use(m.Handler)
+}
+
+func (m *requestDeadline) Handler(ctx *gin.Context) {
+	if m.timeout <= 0 {
+		ctx.Next()
+		return
+	}
+	c, cancel := context.WithTimeout(ctx.Request.Context(), m.timeout)
+	defer cancel()
+	ctx.Request = ctx.Request.WithContext(c)
+	ctx.Next()
+}
+
+// NatsContext creates the context for a NATS request, bounded by the deadline
+// the caller sent along with it.
+func NatsContext(req micro.NatsRequest) (context.Context, context.CancelFunc) {
+	if t, ok := deadline.Parse(req.Headers().Get(deadline.HeaderDeadline)); ok {
+		return context.WithDeadline(context.Background(), t)
+	}
+	return context.WithCancel(context.Background())
+}
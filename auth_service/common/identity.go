@@ -0,0 +1,166 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/afteracademy/gomicro/contracts/identity"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/afteracademy/goserve/v2/redis"
+	natsmicro "github.com/nats-io/nats.go/micro"
+)
+
+type ServiceIdentity interface {
+	Verify(ctx context.Context, subject string, headers natsmicro.Headers, data []byte) error
+	Guard(handler micro.NatsHandlerFunc) micro.NatsHandlerFunc
+}
+
+// DefaultSignatureTTL is how old a signature may be when no TTL is configured.
+const DefaultSignatureTTL = 30 * time.Second
+
+// NonceStore remembers the nonces of the requests that were let through, so
+// that a captured request is not let through again within the TTL. It is
+// shared by the instances, any of which may receive the replay.
+type NonceStore interface {
+	// Claim records the key and reports false when it was recorded before
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+type redisNonceStore struct {
+	store redis.Store
+}
+
+func NewNonceStore(store redis.Store) NonceStore {
+	return &redisNonceStore{
+		store: store,
+	}
+}
+
+func (s *redisNonceStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return s.store.GetInstance().SetNX(ctx, key, 1, ttl).Result()
+}
+
+type serviceIdentity struct {
+	secrets   map[string][]byte
+	allowlist map[string]map[string]bool
+	ttl       time.Duration
+	nonces    NonceStore
+}
+
+// NewServiceIdentity parses the caller configuration of the form
+//
+//	secrets:   blog:secret1,other:secret2
+//	allowlist: blog:auth.authentication|auth.profile.user,other:auth.authorization
+//
+// A ttl of 0 falls back to DefaultSignatureTTL.
+func NewServiceIdentity(secrets string, allowlist string, ttl time.Duration, nonces NonceStore) (ServiceIdentity, error) {
+	if ttl <= 0 {
+		ttl = DefaultSignatureTTL
+	}
+
+	secretPairs, err := parsePairs(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	allowlistPairs, err := parsePairs(allowlist)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &serviceIdentity{
+		secrets:   make(map[string][]byte),
+		allowlist: make(map[string]map[string]bool),
+		ttl:       ttl,
+		nonces:    nonces,
+	}
+
+	for service, secret := range secretPairs {
+		s.secrets[service] = []byte(secret)
+	}
+
+	for service, subjects := range allowlistPairs {
+		allowed := make(map[string]bool)
+		for _, subject := range strings.Split(subjects, "|") {
+			if subject = strings.TrimSpace(subject); subject != "" {
+				allowed[subject] = true
+			}
+		}
+		s.allowlist[service] = allowed
+	}
+
+	return s, nil
+}
+
+func (s *serviceIdentity) Verify(ctx context.Context, subject string, headers natsmicro.Headers, data []byte) error {
+	service := headers.Get(identity.HeaderService)
+	nonce := headers.Get(identity.HeaderNonce)
+
+	secret, ok := s.secrets[service]
+	if !ok {
+		return network.NewUnauthorizedError("permission denied: unknown service", identity.ErrMissingIdentity)
+	}
+
+	err := identity.Verify(
+		secret,
+		service,
+		subject,
+		headers.Get(identity.HeaderTimestamp),
+		nonce,
+		headers.Get(identity.HeaderSignature),
+		data,
+		s.ttl,
+		time.Now(),
+	)
+	if err != nil {
+		return network.NewUnauthorizedError("permission denied: "+err.Error(), err)
+	}
+
+	if !s.allowlist[service][subject] {
+		return network.NewForbiddenError("permission denied: "+service+" may not call "+subject, nil)
+	}
+
+	// the timestamp is accepted within the ttl on both sides of now
+	fresh, err := s.nonces.Claim(ctx, "identity_nonce_"+service+"_"+nonce, 2*s.ttl)
+	if err != nil {
+		return network.NewInternalServerError("service identity not verified", err)
+	}
+	if !fresh {
+		return network.NewUnauthorizedError("permission denied: "+identity.ErrReplayed.Error(), identity.ErrReplayed)
+	}
+
+	return nil
+}
+
+func (s *serviceIdentity) Guard(handler micro.NatsHandlerFunc) micro.NatsHandlerFunc {
+	return func(req micro.NatsRequest) {
+		ctx, cancel := NatsContext(req)
+		defer cancel()
+
+		err := s.Verify(ctx, req.Subject(), req.Headers(), req.Data())
+		if err != nil {
+			micro.RespondNatsError(req, err)
+			return
+		}
+		handler(req)
+	}
+}
+
+func parsePairs(value string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, val, found := strings.Cut(item, ":")
+		if !found || key == "" || val == "" {
+			return nil, fmt.Errorf("invalid service identity entry: %s", item)
+		}
+		pairs[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return pairs, nil
+}
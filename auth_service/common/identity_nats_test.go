@@ -0,0 +1,142 @@
+package common
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	authv1 "github.com/afteracademy/gomicro/contracts/auth/v1"
+	"github.com/afteracademy/gomicro/contracts/identity"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	natsmicro "github.com/nats-io/nats.go/micro"
+)
+
+type natsReply struct {
+	Data  *authv1.Text `json:"data"`
+	Error *string      `json:"error"`
+}
+
+// startAuth serves auth.authentication behind the guard on an embedded NATS
+// server, and returns a client connection to it.
+func startAuth(t *testing.T, s ServiceIdentity) *nats.Conn {
+	t.Helper()
+
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	t.Cleanup(ns.Shutdown)
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	nc, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(nc.Close)
+
+	svc, err := natsmicro.AddService(nc, natsmicro.Config{Name: "auth", Version: "1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { svc.Stop() })
+
+	handler := func(req micro.NatsRequest) {
+		req.RespondJSON(micro.NewMessage(authv1.NewText("authenticated"), nil))
+	}
+	err = svc.AddGroup("auth").AddEndpoint(authv1.NATS_ENDPOINT_AUTH, s.Guard(handler))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return nc
+}
+
+func request(t *testing.T, nc *nats.Conn, msg *nats.Msg) *natsReply {
+	t.Helper()
+
+	res, err := nc.RequestMsg(msg, 2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reply natsReply
+	if err = json.Unmarshal(res.Data, &reply); err != nil {
+		t.Fatal(err)
+	}
+	return &reply
+}
+
+func signedMsg(secret, service, subject string, data []byte) *nats.Msg {
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	for k, v := range identity.NewHeaders([]byte(secret), service, subject, data, time.Now()) {
+		msg.Header.Set(k, v)
+	}
+	return msg
+}
+
+func TestServiceIdentityOverNats(t *testing.T) {
+	s, err := NewServiceIdentity("blog:"+testSecret, "blog:"+authv1.NATS_TOPIC_AUTH, 0, newMemoryNonces())
+	if err != nil {
+		t.Fatal(err)
+	}
+	nc := startAuth(t, s)
+	data := []byte(`{"value":"token"}`)
+
+	t.Run("signed caller", func(t *testing.T) {
+		reply := request(t, nc, signedMsg(testSecret, "blog", authv1.NATS_TOPIC_AUTH, data))
+		if reply.Error != nil || reply.Data == nil || reply.Data.Value != "authenticated" {
+			t.Fatalf("got %+v", reply)
+		}
+	})
+
+	t.Run("unsigned caller", func(t *testing.T) {
+		msg := nats.NewMsg(authv1.NATS_TOPIC_AUTH)
+		msg.Data = data
+		reply := request(t, nc, msg)
+		if reply.Error == nil || !strings.HasPrefix(*reply.Error, "401:") {
+			t.Fatalf("got %+v", reply)
+		}
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		msg := signedMsg(testSecret, "blog", authv1.NATS_TOPIC_AUTH, data)
+		msg.Data = []byte(`{"value":"other"}`)
+		reply := request(t, nc, msg)
+		if reply.Error == nil || !strings.HasPrefix(*reply.Error, "401:") {
+			t.Fatalf("got %+v", reply)
+		}
+	})
+
+	t.Run("replayed request", func(t *testing.T) {
+		msg := signedMsg(testSecret, "blog", authv1.NATS_TOPIC_AUTH, data)
+		if reply := request(t, nc, msg); reply.Error != nil {
+			t.Fatalf("first request rejected: %s", *reply.Error)
+		}
+		replay := nats.NewMsg(msg.Subject)
+		replay.Data = msg.Data
+		replay.Header = msg.Header
+		reply := request(t, nc, replay)
+		if reply.Error == nil || !strings.HasPrefix(*reply.Error, "401:") {
+			t.Fatalf("got %+v", reply)
+		}
+	})
+}
+
+func TestServiceIdentityAllowlistOverNats(t *testing.T) {
+	s, err := NewServiceIdentity("blog:"+testSecret+",other:secret", "blog:"+authv1.NATS_TOPIC_AUTH+",other:"+authv1.NATS_TOPIC_AUTHZ, 0, newMemoryNonces())
+	if err != nil {
+		t.Fatal(err)
+	}
+	nc := startAuth(t, s)
+
+	reply := request(t, nc, signedMsg("secret", "other", authv1.NATS_TOPIC_AUTH, []byte(`{"value":"token"}`)))
+	if reply.Error == nil || !strings.HasPrefix(*reply.Error, "403:") {
+		t.Fatalf("got %+v", reply)
+	}
+}
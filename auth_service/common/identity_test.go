@@ -0,0 +1,196 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/afteracademy/gomicro/contracts/identity"
+	"github.com/afteracademy/goserve/v2/network"
+	natsmicro "github.com/nats-io/nats.go/micro"
+)
+
+const (
+	testSubject = "auth.profile.user"
+	testSecret  = "changeit"
+)
+
+type fakeRequest struct {
+	natsmicro.Request
+	subject  string
+	headers  natsmicro.Headers
+	data     []byte
+	response any
+}
+
+func (r *fakeRequest) Subject() string            { return r.subject }
+func (r *fakeRequest) Headers() natsmicro.Headers { return r.headers }
+func (r *fakeRequest) Data() []byte               { return r.data }
+
+func (r *fakeRequest) RespondJSON(v any, _ ...natsmicro.RespondOpt) error {
+	r.response = v
+	return nil
+}
+
+type memoryNonces struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func newMemoryNonces() *memoryNonces {
+	return &memoryNonces{seen: make(map[string]bool)}
+}
+
+func (n *memoryNonces) Claim(_ context.Context, key string, _ time.Duration) (bool, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.seen[key] {
+		return false, nil
+	}
+	n.seen[key] = true
+	return true, nil
+}
+
+func signedHeaders(secret, service, subject string, data []byte, now time.Time) natsmicro.Headers {
+	headers := natsmicro.Headers{}
+	for k, v := range identity.NewHeaders([]byte(secret), service, subject, data, now) {
+		headers[k] = []string{v}
+	}
+	return headers
+}
+
+func TestServiceIdentityGuard(t *testing.T) {
+	s, err := NewServiceIdentity(
+		"blog:"+testSecret+",other:secret",
+		"blog:"+testSubject+",other:auth.authorization",
+		30*time.Second,
+		newMemoryNonces(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte(`{"value":"token"}`)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		subject string
+		headers natsmicro.Headers
+		allowed bool
+	}{
+		{
+			name:    "signed caller",
+			subject: testSubject,
+			headers: signedHeaders(testSecret, "blog", testSubject, data, now),
+			allowed: true,
+		},
+		{
+			name:    "unsigned caller",
+			subject: testSubject,
+			headers: natsmicro.Headers{},
+		},
+		{
+			name:    "expired signature",
+			subject: testSubject,
+			headers: signedHeaders(testSecret, "blog", testSubject, data, now.Add(-time.Minute)),
+		},
+		{
+			name:    "wrong secret",
+			subject: testSubject,
+			headers: signedHeaders("guess", "blog", testSubject, data, now),
+		},
+		{
+			name:    "unknown service",
+			subject: testSubject,
+			headers: signedHeaders(testSecret, "stranger", testSubject, data, now),
+		},
+		{
+			name:    "subject not in the allowlist",
+			subject: testSubject,
+			headers: signedHeaders("secret", "other", testSubject, data, now),
+		},
+		{
+			name:    "signed for another subject",
+			subject: testSubject,
+			headers: signedHeaders(testSecret, "blog", "auth.authentication", data, now),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &fakeRequest{subject: tt.subject, headers: tt.headers, data: data}
+			called := false
+			s.Guard(func(natsmicro.Request) { called = true })(req)
+
+			if called != tt.allowed {
+				t.Fatalf("handler called = %v, want %v", called, tt.allowed)
+			}
+			if !tt.allowed && req.response == nil {
+				t.Fatal("rejected request got no response")
+			}
+		})
+	}
+}
+
+func TestServiceIdentityUnknownServiceMessage(t *testing.T) {
+	s, err := NewServiceIdentity("blog:"+testSecret, "blog:"+testSubject, 0, newMemoryNonces())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers := signedHeaders(testSecret, "<script>", testSubject, nil, time.Now())
+	err = s.Verify(context.Background(), testSubject, headers, nil)
+	apiErr, ok := err.(network.ApiError)
+	if !ok || apiErr.GetMessage() != "permission denied: unknown service" {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestServiceIdentityDefaultTTL(t *testing.T) {
+	s, err := NewServiceIdentity("blog:"+testSecret, "blog:"+testSubject, 0, newMemoryNonces())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers := signedHeaders(testSecret, "blog", testSubject, nil, time.Now().Add(-time.Second))
+	if err = s.Verify(context.Background(), testSubject, headers, nil); err != nil {
+		t.Fatalf("fresh signature rejected with a ttl of 0: %v", err)
+	}
+}
+
+func TestServiceIdentityReplay(t *testing.T) {
+	s, err := NewServiceIdentity("blog:"+testSecret, "blog:"+testSubject, 0, newMemoryNonces())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers := signedHeaders(testSecret, "blog", testSubject, nil, time.Now())
+	if err = s.Verify(context.Background(), testSubject, headers, nil); err != nil {
+		t.Fatalf("first request rejected: %v", err)
+	}
+	if err = s.Verify(context.Background(), testSubject, headers, nil); err == nil {
+		t.Fatal("replayed request accepted")
+	}
+}
+
+func TestNewServiceIdentityInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		secrets   string
+		allowlist string
+	}{
+		{name: "secret without service", secrets: ":secret", allowlist: "blog:" + testSubject},
+		{name: "service without secret", secrets: "blog", allowlist: "blog:" + testSubject},
+		{name: "allowlist without subjects", secrets: "blog:" + testSecret, allowlist: "blog:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewServiceIdentity(tt.secrets, tt.allowlist, time.Second, newMemoryNonces()); err == nil {
+				t.Fatal("invalid configuration accepted")
+			}
+		})
+	}
+}
@@ -8,9 +8,10 @@ import (
 
 type Env struct {
 	// server
-	GoMode     string `mapstructure:"GO_MODE"`
-	ServerHost string `mapstructure:"SERVER_HOST"`
-	ServerPort uint16 `mapstructure:"SERVER_PORT"`
+	GoMode                  string `mapstructure:"GO_MODE"`
+	ServerHost              string `mapstructure:"SERVER_HOST"`
+	ServerPort              uint16 `mapstructure:"SERVER_PORT"`
+	ServerRequestTimeoutSec uint16 `mapstructure:"SERVER_REQUEST_TIMEOUT_SEC"`
 	// database
 	DBHost         string `mapstructure:"DB_HOST"`
 	DBName         string `mapstructure:"DB_NAME"`
@@ -30,6 +31,10 @@ type Env struct {
 	NatsServiceName    string `mapstructure:"NATS_SERVICE_NAME"`
 	NatsServiceVersion string `mapstructure:"NATS_SERVICE_VERSION"`
 	NatsTimeoutSec     uint16 `mapstructure:"NATS_TIMEOUT_SEC"`
+	// service identity
+	NatsCallerSecrets   string `mapstructure:"NATS_CALLER_SECRETS"`
+	NatsCallerAllowlist string `mapstructure:"NATS_CALLER_ALLOWLIST"`
+	NatsSignatureTTLSec uint16 `mapstructure:"NATS_SIGNATURE_TTL_SEC"`
 	// keys
 	RSAPrivateKeyPath string `mapstructure:"RSA_PRIVATE_KEY_PATH"`
 	RSAPublicKeyPath  string `mapstructure:"RSA_PUBLIC_KEY_PATH"`
@@ -2,11 +2,14 @@ package startup
 
 import (
 	"context"
+	"log"
+	"time"
 
 	"github.com/afteracademy/gomicro/auth-service/api/auth"
 	authMW "github.com/afteracademy/gomicro/auth-service/api/auth/middleware"
 	"github.com/afteracademy/gomicro/auth-service/api/health"
 	"github.com/afteracademy/gomicro/auth-service/api/user"
+	"github.com/afteracademy/gomicro/auth-service/common"
 	"github.com/afteracademy/gomicro/auth-service/config"
 	"github.com/afteracademy/goserve/v2/micro"
 	coreMW "github.com/afteracademy/goserve/v2/middleware"
@@ -23,6 +26,7 @@ type module struct {
 	DB            postgres.Database
 	Store         redis.Store
 	NatsClient    micro.NatsClient
+	Identity      common.ServiceIdentity
 	UserService   user.Service
 	AuthService   auth.Service
 	HealthService health.Service
@@ -35,14 +39,15 @@ func (m *module) GetInstance() *module {
 func (m *module) Controllers() []micro.Controller {
 	return []micro.Controller{
 		health.NewController(m.HealthService),
-		auth.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.AuthService, m.UserService),
-		user.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.UserService),
+		auth.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.Identity, m.AuthService, m.UserService),
+		user.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.Identity, m.UserService),
 	}
 }
 
 func (m *module) RootMiddlewares() []network.RootMiddleware {
 	return []network.RootMiddleware{
 		coreMW.NewErrorCatcher(), // NOTE: this should be the first handler to be mounted
+		common.NewRequestDeadline(time.Duration(m.Env.ServerRequestTimeoutSec) * time.Second),
 		coreMW.NewNotFound(),
 	}
 }
@@ -65,6 +70,15 @@ func NewModule(
 	userService := user.NewService(db)
 	authService := auth.NewService(db, env, userService)
 	healthService := health.NewService()
+	identity, err := common.NewServiceIdentity(
+		env.NatsCallerSecrets,
+		env.NatsCallerAllowlist,
+		time.Duration(env.NatsSignatureTTLSec)*time.Second,
+		common.NewNonceStore(store),
+	)
+	if err != nil {
+		log.Fatal("Error loading service identity: ", err)
+	}
 
 	return &module{
 		Context:       context,
@@ -72,6 +86,7 @@ func NewModule(
 		DB:            db,
 		Store:         store,
 		NatsClient:    natsClient,
+		Identity:      identity,
 		UserService:   userService,
 		AuthService:   authService,
 		HealthService: healthService,
@@ -1,21 +1,20 @@
 package message
 
 import (
-	"github.com/google/uuid"
+	authv1 "github.com/afteracademy/gomicro/contracts/auth/v1"
 )
 
-type RoleCode string
+type RoleCode = authv1.RoleCode
 
 const (
-	RoleCodeLearner RoleCode = "LEARNER"
-	RoleCodeAdmin   RoleCode = "ADMIN"
-	RoleCodeAuthor  RoleCode = "AUTHOR"
-	RoleCodeEditor  RoleCode = "EDITOR"
+	RoleCodeLearner = authv1.RoleCodeLearner
+	RoleCodeAdmin   = authv1.RoleCodeAdmin
+	RoleCodeAuthor  = authv1.RoleCodeAuthor
+	RoleCodeEditor  = authv1.RoleCodeEditor
 )
 
-type User struct {
-	ID            uuid.UUID `json:"id" validate:"required,uuid"`
-	Name          string    `json:"name"`
-	Email         string    `json:"email"`
-	ProfilePicURL *string   `json:"profilePicUrl,omitempty"`
-}
+type User = authv1.User
+
+type UserIds = authv1.UserIds
+
+type Users = authv1.Users
@@ -1,13 +1,11 @@
 package message
 
-type UserRole struct {
-	User  *User    `json:"user"`
-	Roles []string `json:"roles"`
-}
+import (
+	authv1 "github.com/afteracademy/gomicro/contracts/auth/v1"
+)
+
+type UserRole = authv1.UserRole
 
 func NewUserRole(user *User, roles ...string) *UserRole {
-	return &UserRole{
-		User:  user,
-		Roles: roles,
-	}
+	return authv1.NewUserRole(user, roles...)
 }
@@ -23,7 +23,11 @@ func (m *authenticationProvider) Middleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader(network.AuthorizationHeader)
 
-		user, err := m.authService.Authenticate(authHeader)
+		user, err := m.authService.Authenticate(ctx.Request.Context(), authHeader)
+		if common.IsServiceUnavailable(err) {
+			common.SendMixedError(ctx, err)
+			return
+		}
 		if err != nil {
 			network.SendUnauthorizedError(ctx, err.Error(), err)
 			return
@@ -23,7 +23,11 @@ func (m *authorizationProvider) Middleware(roleNames ...string) gin.HandlerFunc
 	return func(ctx *gin.Context) {
 		user := m.MustGetUser(ctx)
 
-		err := m.authService.Authorize(user, roleNames...)
+		err := m.authService.Authorize(ctx.Request.Context(), user, roleNames...)
+		if common.IsServiceUnavailable(err) {
+			common.SendMixedError(ctx, err)
+			return
+		}
 		if err != nil {
 			network.SendForbiddenError(ctx, err.Error(), err)
 			return
@@ -1,43 +1,84 @@
 package auth
 
 import (
+	"context"
+	"time"
+
 	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
-	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	authv1 "github.com/afteracademy/gomicro/contracts/auth/v1"
 	"github.com/google/uuid"
 )
 
-const NATS_TOPIC_AUTH = "auth.authentication"
-const NATS_TOPIC_AUTHZ = "auth.authorization"
-const NATS_TOPIC_USERPROFILE = "auth.profile.user"
+const NATS_TOPIC_AUTH = authv1.NATS_TOPIC_AUTH
+const NATS_TOPIC_AUTHZ = authv1.NATS_TOPIC_AUTHZ
+const NATS_TOPIC_USERPROFILE = authv1.NATS_TOPIC_USERPROFILE
+const NATS_TOPIC_USERPROFILES = authv1.NATS_TOPIC_USERPROFILES
 
 type Service interface {
-	Authenticate(token string) (*message.User, error)
-	Authorize(user *message.User, roles ...string) error
-	FindUserPublicProfile(userId uuid.UUID) (*message.User, error)
+	Authenticate(ctx context.Context, token string) (*message.User, error)
+	Authorize(ctx context.Context, user *message.User, roles ...string) error
+	FindUserPublicProfile(ctx context.Context, userId uuid.UUID) (*message.User, error)
+	FindUserPublicProfiles(ctx context.Context, userIds []uuid.UUID) (map[uuid.UUID]*message.User, error)
 }
 
 type service struct {
-	natsClient micro.NatsClient
+	natsCaller common.NatsCaller
 }
 
-func NewService(natsClient micro.NatsClient) Service {
+func NewService(natsCaller common.NatsCaller) Service {
+	// all auth lookups are reads, so they are safe to retry
+	natsCaller.SetPolicy(NATS_TOPIC_AUTH, common.NatsPolicy{Timeout: 5 * time.Second, Retries: 2, Idempotent: true})
+	natsCaller.SetPolicy(NATS_TOPIC_AUTHZ, common.NatsPolicy{Timeout: 5 * time.Second, Retries: 2, Idempotent: true})
+	natsCaller.SetPolicy(NATS_TOPIC_USERPROFILE, common.NatsPolicy{Timeout: 3 * time.Second, Retries: 1, Idempotent: true})
+	natsCaller.SetPolicy(NATS_TOPIC_USERPROFILES, common.NatsPolicy{Timeout: 5 * time.Second, Retries: 1, Idempotent: true})
+
 	return &service{
-		natsClient: natsClient,
+		natsCaller: natsCaller,
 	}
 }
 
-func (s *service) Authenticate(token string) (*message.User, error) {
+func (s *service) Authenticate(ctx context.Context, token string) (*message.User, error) {
 	msg := message.NewText(token)
-	return micro.RequestNats[message.Text, message.User](s.natsClient, NATS_TOPIC_AUTH, msg)
+	return common.RequestNats[message.Text, message.User](ctx, s.natsCaller, NATS_TOPIC_AUTH, msg)
 }
 
-func (s *service) Authorize(user *message.User, roles ...string) error {
+func (s *service) Authorize(ctx context.Context, user *message.User, roles ...string) error {
 	msg := message.NewUserRole(user, roles...)
-	_, err := micro.RequestNats[message.UserRole, message.User](s.natsClient, NATS_TOPIC_AUTHZ, msg)
+	_, err := common.RequestNats[message.UserRole, message.User](ctx, s.natsCaller, NATS_TOPIC_AUTHZ, msg)
 	return err
 }
 
-func (s *service) FindUserPublicProfile(userId uuid.UUID) (*message.User, error) {
+func (s *service) FindUserPublicProfile(ctx context.Context, userId uuid.UUID) (*message.User, error) {
 	msg := message.NewText(userId.String())
-	return micro.RequestNats[message.Text, message.User](s.natsClient, NATS_TOPIC_USERPROFILE, msg)
+	return common.RequestNats[message.Text, message.User](ctx, s.natsCaller, NATS_TOPIC_USERPROFILE, msg)
+}
+
+// FindUserPublicProfiles looks the users up with one request per
+// authv1.MaxUserIds ids. The users that were not found are missing from the
+// map.
+func (s *service) FindUserPublicProfiles(ctx context.Context, userIds []uuid.UUID) (map[uuid.UUID]*message.User, error) {
+	ids := make([]uuid.UUID, 0, len(userIds))
+	seen := make(map[uuid.UUID]bool, len(userIds))
+	for _, id := range userIds {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	users := make(map[uuid.UUID]*message.User, len(ids))
+	for start := 0; start < len(ids); start += authv1.MaxUserIds {
+		end := min(start+authv1.MaxUserIds, len(ids))
+		msg := authv1.NewUserIds(ids[start:end])
+		res, err := common.RequestNats[message.UserIds, message.Users](ctx, s.natsCaller, NATS_TOPIC_USERPROFILES, msg)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range res.Users {
+			users[user.ID] = user
+		}
+	}
+
+	return users, nil
 }
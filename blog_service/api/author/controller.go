@@ -3,6 +3,9 @@ package author
 import (
 	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
 	"github.com/afteracademy/gomicro/blog-service/api/author/dto"
+	blogDto "github.com/afteracademy/gomicro/blog-service/api/blog/dto"
+	reviewDto "github.com/afteracademy/gomicro/blog-service/api/review/dto"
+	revisionDto "github.com/afteracademy/gomicro/blog-service/api/revision/dto"
 	"github.com/afteracademy/gomicro/blog-service/common"
 	coredto "github.com/afteracademy/goserve/v2/dto"
 	"github.com/afteracademy/goserve/v2/micro"
@@ -41,6 +44,12 @@ func (c *controller) MountRoutes(group *gin.RouterGroup) {
 	group.GET("/drafts", c.getDraftsBlogsHandler)
 	group.GET("/submitted", c.getSubmittedBlogsHandler)
 	group.GET("/published", c.getPublishedBlogsHandler)
+	group.GET("/revisions/id/:id", c.getRevisionsHandler)
+	group.GET("/revisions/diff/id/:id", c.getRevisionsDiffHandler)
+	group.GET("/transitions/id/:id", c.getTransitionsHandler)
+	group.GET("/revision/id/:id/:number", c.getRevisionHandler)
+	group.PUT("/revision/restore/id/:id/:number", c.restoreRevisionHandler)
+	group.POST("/review/id/:id", c.postReviewHandler)
 }
 
 func (c *controller) postBlogHandler(ctx *gin.Context) {
@@ -52,9 +61,9 @@ func (c *controller) postBlogHandler(ctx *gin.Context) {
 
 	user := c.MustGetUser(ctx)
 
-	b, err := c.service.CreateBlog(body, user)
+	b, err := c.service.CreateBlog(ctx.Request.Context(), body, user)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
@@ -68,14 +77,22 @@ func (c *controller) updateBlogHandler(ctx *gin.Context) {
 		return
 	}
 
+	version, err := common.ReqVersion(ctx, body.Version)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+	body.Version = &version
+
 	user := c.MustGetUser(ctx)
 
-	b, err := c.service.UpdateBlog(body, user)
+	b, err := c.service.UpdateBlog(ctx.Request.Context(), body, user)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
+	common.SetVersion(ctx, b.Version)
 	network.SendSuccessDataResponse(ctx, "blog updated successfully", b)
 }
 
@@ -88,12 +105,13 @@ func (c *controller) getBlogHandler(ctx *gin.Context) {
 
 	user := c.MustGetUser(ctx)
 
-	blog, err := c.service.GetBlogById(mongoId.ID, user)
+	blog, err := c.service.GetBlogById(ctx.Request.Context(), mongoId.ID, user)
 	if err != nil {
 		network.SendNotFoundError(ctx, mongoId.Id+" not found", err)
 		return
 	}
 
+	common.SetVersion(ctx, blog.Version)
 	network.SendSuccessDataResponse(ctx, "success", blog)
 }
 
@@ -106,9 +124,9 @@ func (c *controller) submitBlogHandler(ctx *gin.Context) {
 
 	user := c.MustGetUser(ctx)
 
-	err = c.service.BlogSubmission(mongoId.ID, user, true)
+	err = c.service.BlogSubmission(ctx.Request.Context(), mongoId.ID, user, true)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
@@ -124,9 +142,9 @@ func (c *controller) withdrawBlogHandler(ctx *gin.Context) {
 
 	user := c.MustGetUser(ctx)
 
-	err = c.service.BlogSubmission(mongoId.ID, user, false)
+	err = c.service.BlogSubmission(ctx.Request.Context(), mongoId.ID, user, false)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
@@ -142,9 +160,9 @@ func (c *controller) deleteBlogHandler(ctx *gin.Context) {
 
 	user := c.MustGetUser(ctx)
 
-	err = c.service.DeactivateBlog(mongoId.ID, user)
+	err = c.service.DeactivateBlog(ctx.Request.Context(), mongoId.ID, user)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
@@ -152,7 +170,30 @@ func (c *controller) deleteBlogHandler(ctx *gin.Context) {
 }
 
 func (c *controller) getDraftsBlogsHandler(ctx *gin.Context) {
-	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
+	if common.HasCursor(ctx) {
+		c.getDraftsBlogsByCursorHandler(ctx)
+		return
+	}
+
+	query, err := network.ReqQuery[blogDto.ListQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	blogs, err := c.service.GetPaginatedDrafts(ctx.Request.Context(), user, query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", blogs)
+}
+
+func (c *controller) getDraftsBlogsByCursorHandler(ctx *gin.Context) {
+	query, err := network.ReqQuery[blogDto.CursorQuery](ctx)
 	if err != nil {
 		network.SendBadRequestError(ctx, err.Error(), err)
 		return
@@ -160,17 +201,40 @@ func (c *controller) getDraftsBlogsHandler(ctx *gin.Context) {
 
 	user := c.MustGetUser(ctx)
 
-	blogs, err := c.service.GetPaginatedDrafts(user, pagination)
+	page, err := c.service.GetDraftsByCursor(ctx.Request.Context(), user, query)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
-	network.SendSuccessDataResponse(ctx, "success", &blogs)
+	network.SendSuccessDataResponse(ctx, "success", page)
 }
 
 func (c *controller) getSubmittedBlogsHandler(ctx *gin.Context) {
-	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
+	if common.HasCursor(ctx) {
+		c.getSubmittedBlogsByCursorHandler(ctx)
+		return
+	}
+
+	query, err := network.ReqQuery[blogDto.ListQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	blogs, err := c.service.GetPaginatedSubmitted(ctx.Request.Context(), user, query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", blogs)
+}
+
+func (c *controller) getSubmittedBlogsByCursorHandler(ctx *gin.Context) {
+	query, err := network.ReqQuery[blogDto.CursorQuery](ctx)
 	if err != nil {
 		network.SendBadRequestError(ctx, err.Error(), err)
 		return
@@ -178,16 +242,63 @@ func (c *controller) getSubmittedBlogsHandler(ctx *gin.Context) {
 
 	user := c.MustGetUser(ctx)
 
-	blogs, err := c.service.GetPaginatedSubmitted(user, pagination)
+	page, err := c.service.GetSubmittedByCursor(ctx.Request.Context(), user, query)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
-	network.SendSuccessDataResponse(ctx, "success", &blogs)
+	network.SendSuccessDataResponse(ctx, "success", page)
 }
 
 func (c *controller) getPublishedBlogsHandler(ctx *gin.Context) {
+	if common.HasCursor(ctx) {
+		c.getPublishedBlogsByCursorHandler(ctx)
+		return
+	}
+
+	query, err := network.ReqQuery[blogDto.ListQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	blogs, err := c.service.GetPaginatedPublished(ctx.Request.Context(), user, query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", blogs)
+}
+
+func (c *controller) getPublishedBlogsByCursorHandler(ctx *gin.Context) {
+	query, err := network.ReqQuery[blogDto.CursorQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	page, err := c.service.GetPublishedByCursor(ctx.Request.Context(), user, query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", page)
+}
+
+func (c *controller) getRevisionsHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
 	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
 	if err != nil {
 		network.SendBadRequestError(ctx, err.Error(), err)
@@ -196,11 +307,125 @@ func (c *controller) getPublishedBlogsHandler(ctx *gin.Context) {
 
 	user := c.MustGetUser(ctx)
 
-	blogs, err := c.service.GetPaginatedPublished(user, pagination)
+	revisions, err := c.service.GetRevisions(ctx.Request.Context(), mongoId.ID, user, pagination)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", &revisions)
+}
+
+func (c *controller) getRevisionsDiffHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	query, err := network.ReqQuery[revisionDto.DiffQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	diff, err := c.service.DiffRevisions(ctx.Request.Context(), mongoId.ID, query, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", diff)
+}
+
+func (c *controller) getRevisionHandler(ctx *gin.Context) {
+	revisionId, err := network.ReqParams[revisionDto.RevisionId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	revision, err := c.service.GetRevision(ctx.Request.Context(), revisionId.ID, revisionId.Number, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", revision)
+}
+
+func (c *controller) restoreRevisionHandler(ctx *gin.Context) {
+	revisionId, err := network.ReqParams[revisionDto.RevisionId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	version, err := common.ReqVersion(ctx, nil)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	b, err := c.service.RestoreRevision(ctx.Request.Context(), revisionId.ID, revisionId.Number, version, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	common.SetVersion(ctx, b.Version)
+	network.SendSuccessDataResponse(ctx, "revision restored successfully", b)
+}
+
+func (c *controller) postReviewHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	body, err := network.ReqBody[reviewDto.CreateReview](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	if body.ParentId != nil && body.ParentID == nil {
+		network.SendBadRequestError(ctx, "parentId is not a valid id", nil)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	review, err := c.service.AddReview(ctx.Request.Context(), mongoId.ID, body, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "review added successfully", review)
+}
+
+func (c *controller) getTransitionsHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	transitions, err := c.service.GetTransitions(ctx.Request.Context(), mongoId.ID, user)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
-	network.SendSuccessDataResponse(ctx, "success", &blogs)
+	network.SendSuccessDataResponse(ctx, "success", &transitions)
 }
@@ -11,7 +11,7 @@ type CreateBlog struct {
 	DraftText   string   `json:"draftText" validate:"required,max=50000"`
 	Slug        string   `json:"slug" validate:"required,min=3,max=200"`
 	ImgURL      string   `json:"imgUrl" validate:"required,uri,max=200"`
-	Tags        []string `json:"tags" validate:"required,min=1,dive,uppercase"`
+	Tags        []string `json:"tags" validate:"required,min=1"`
 }
 
 func EmptyCreateBlog() *CreateBlog {
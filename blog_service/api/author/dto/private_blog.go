@@ -5,28 +5,29 @@ import (
 
 	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
 	"github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	reviewDto "github.com/afteracademy/gomicro/blog-service/api/review/dto"
 	"github.com/afteracademy/goserve/v2/utility"
 	"github.com/go-playground/validator/v10"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type PrivateBlog struct {
-	ID          primitive.ObjectID `json:"_id" binding:"required" validate:"required"`
-	Title       string             `json:"title" validate:"required,min=3,max=500"`
-	Description string             `json:"description" validate:"required,min=3,max=2000"`
-	Text        *string            `json:"text,omitempty" validate:"omitempty,max=50000"`
-	DraftText   string             `json:"draftText" validate:"required"`
-	Slug        string             `json:"slug" validate:"required,min=3,max=200"`
-	Author      *InfoAuthor        `json:"author,omitempty" validate:"required,omitempty"`
-	ImgURL      *string            `json:"imgUrl,omitempty" validate:"omitempty,uri,max=200"`
-	Score       *float64           `json:"score,omitempty" validate:"omitempty,min=0,max=1"`
-	Tags        *[]string          `json:"tags,omitempty" validate:"omitempty,dive,uppercase"`
-	Submitted   bool               `json:"submitted" validate:"-"`
-	Drafted     bool               `json:"drafted" validate:"-"`
-	Published   bool               `json:"published" validate:"-"`
-	PublishedAt *time.Time         `json:"publishedAt,omitempty"`
-	CreatedAt   time.Time          `json:"createdAt" validate:"required"`
-	UpdatedAt   time.Time          `json:"updatedAt" validate:"required"`
+	ID          primitive.ObjectID  `json:"_id" binding:"required" validate:"required"`
+	Title       string              `json:"title" validate:"required,min=3,max=500"`
+	Description string              `json:"description" validate:"required,min=3,max=2000"`
+	Text        *string             `json:"text,omitempty" validate:"omitempty,max=50000"`
+	DraftText   string              `json:"draftText" validate:"required"`
+	Slug        string              `json:"slug" validate:"required,min=3,max=200"`
+	Author      *InfoAuthor         `json:"author,omitempty" validate:"required,omitempty"`
+	ImgURL      *string             `json:"imgUrl,omitempty" validate:"omitempty,uri,max=200"`
+	Score       *float64            `json:"score,omitempty" validate:"omitempty,min=0,max=1"`
+	Tags        *[]string           `json:"tags,omitempty" validate:"omitempty,dive,uppercase"`
+	State       model.State         `json:"state" validate:"required"`
+	Version     int64               `json:"version" validate:"required"`
+	PublishedAt *time.Time          `json:"publishedAt,omitempty"`
+	CreatedAt   time.Time           `json:"createdAt" validate:"required"`
+	UpdatedAt   time.Time           `json:"updatedAt" validate:"required"`
+	Reviews     []*reviewDto.Review `json:"reviews,omitempty" validate:"-"`
 }
 
 func EmptyInfoPrivateBlog() *PrivateBlog {
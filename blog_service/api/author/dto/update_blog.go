@@ -13,7 +13,8 @@ type UpdateBlog struct {
 	DraftText   *string            `json:"draftText" validate:"omitempty,max=50000"`
 	Slug        *string            `json:"slug" validate:"omitempty,min=3,max=200"`
 	ImgURL      *string            `json:"imgUrl" validate:"omitempty,uri,max=200"`
-	Tags        *[]string          `json:"tags" validate:"omitempty,min=1,dive,uppercase"`
+	Tags        *[]string          `json:"tags" validate:"omitempty,min=1"`
+	Version     *int64             `json:"version,omitempty" validate:"omitempty,min=1"`
 }
 
 func EmptyUpdateBlog() *UpdateBlog {
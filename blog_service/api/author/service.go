@@ -1,60 +1,141 @@
 package author
 
 import (
+	"context"
+	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
 	"github.com/afteracademy/gomicro/blog-service/api/author/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/autocomplete"
 	"github.com/afteracademy/gomicro/blog-service/api/blog"
 	blogDto "github.com/afteracademy/gomicro/blog-service/api/blog/dto"
 	"github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/gomicro/blog-service/api/blogs"
+	"github.com/afteracademy/gomicro/blog-service/api/cache"
+	"github.com/afteracademy/gomicro/blog-service/api/event"
+	eventMsg "github.com/afteracademy/gomicro/blog-service/api/event/message"
+	"github.com/afteracademy/gomicro/blog-service/api/review"
+	reviewDto "github.com/afteracademy/gomicro/blog-service/api/review/dto"
+	reviewModel "github.com/afteracademy/gomicro/blog-service/api/review/model"
+	"github.com/afteracademy/gomicro/blog-service/api/revision"
+	revisionDto "github.com/afteracademy/gomicro/blog-service/api/revision/dto"
+	revisionModel "github.com/afteracademy/gomicro/blog-service/api/revision/model"
+	"github.com/afteracademy/gomicro/blog-service/api/tag"
+	"github.com/afteracademy/gomicro/blog-service/api/workflow"
+	workflowDto "github.com/afteracademy/gomicro/blog-service/api/workflow/dto"
+	workflowModel "github.com/afteracademy/gomicro/blog-service/api/workflow/model"
+	"github.com/afteracademy/gomicro/blog-service/common"
 	"github.com/afteracademy/gomicro/blog-service/utils"
+	blogv1 "github.com/afteracademy/gomicro/contracts/blog/v1"
 	coredto "github.com/afteracademy/goserve/v2/dto"
 	"github.com/afteracademy/goserve/v2/mongo"
 	"github.com/afteracademy/goserve/v2/network"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type Service interface {
-	CreateBlog(createBlogDto *dto.CreateBlog, author *message.User) (*dto.PrivateBlog, error)
-	UpdateBlog(updateBlogDto *dto.UpdateBlog, author *message.User) (*dto.PrivateBlog, error)
-	DeactivateBlog(blogId primitive.ObjectID, author *message.User) error
-	BlogSubmission(blogId primitive.ObjectID, author *message.User, submit bool) error
-	GetBlogById(id primitive.ObjectID, author *message.User) (*dto.PrivateBlog, error)
-	GetPaginatedDrafts(author *message.User, p *coredto.Pagination) ([]*blogDto.InfoBlog, error)
-	GetPaginatedPublished(author *message.User, p *coredto.Pagination) ([]*blogDto.InfoBlog, error)
-	GetPaginatedSubmitted(author *message.User, p *coredto.Pagination) ([]*blogDto.InfoBlog, error)
-	getPaginated(filter bson.M, p *coredto.Pagination, opts *options.FindOptions) ([]*blogDto.InfoBlog, error)
+	CreateBlog(ctx context.Context, createBlogDto *dto.CreateBlog, author *message.User) (*dto.PrivateBlog, error)
+	UpdateBlog(ctx context.Context, updateBlogDto *dto.UpdateBlog, author *message.User) (*dto.PrivateBlog, error)
+	DeactivateBlog(ctx context.Context, blogId primitive.ObjectID, author *message.User) error
+	BlogSubmission(ctx context.Context, blogId primitive.ObjectID, author *message.User, submit bool) error
+	GetBlogById(ctx context.Context, id primitive.ObjectID, author *message.User) (*dto.PrivateBlog, error)
+	GetPaginatedDrafts(ctx context.Context, author *message.User, query *blogDto.ListQuery) (*blogDto.Page[*blogDto.InfoBlog], error)
+	GetPaginatedPublished(ctx context.Context, author *message.User, query *blogDto.ListQuery) (*blogDto.Page[*blogDto.InfoBlog], error)
+	GetPaginatedSubmitted(ctx context.Context, author *message.User, query *blogDto.ListQuery) (*blogDto.Page[*blogDto.InfoBlog], error)
+	GetDraftsByCursor(ctx context.Context, author *message.User, query *blogDto.CursorQuery) (*blogDto.CursorPage[*blogDto.InfoBlog], error)
+	GetPublishedByCursor(ctx context.Context, author *message.User, query *blogDto.CursorQuery) (*blogDto.CursorPage[*blogDto.InfoBlog], error)
+	GetSubmittedByCursor(ctx context.Context, author *message.User, query *blogDto.CursorQuery) (*blogDto.CursorPage[*blogDto.InfoBlog], error)
+	GetRevisions(ctx context.Context, blogId primitive.ObjectID, author *message.User, p *coredto.Pagination) ([]*revisionDto.InfoRevision, error)
+	GetRevision(ctx context.Context, blogId primitive.ObjectID, number int64, author *message.User) (*revisionDto.Revision, error)
+	DiffRevisions(ctx context.Context, blogId primitive.ObjectID, query *revisionDto.DiffQuery, author *message.User) (*revisionDto.RevisionDiff, error)
+	RestoreRevision(ctx context.Context, blogId primitive.ObjectID, number int64, version int64, author *message.User) (*dto.PrivateBlog, error)
+	AddReview(ctx context.Context, blogId primitive.ObjectID, body *reviewDto.CreateReview, author *message.User) (*reviewDto.Review, error)
+	GetTransitions(ctx context.Context, blogId primitive.ObjectID, author *message.User) ([]*workflowDto.Transition, error)
+	getPaginated(ctx context.Context, filter bson.M, query *blogDto.ListQuery) (*blogDto.Page[*blogDto.InfoBlog], error)
+	getByCursor(ctx context.Context, filter bson.M, query *blogDto.CursorQuery) (*blogDto.CursorPage[*blogDto.InfoBlog], error)
 }
 
 type service struct {
-	blogQueryBuilder mongo.QueryBuilder[model.Blog]
-	blogService      blog.Service
+	blogQueryBuilder    mongo.QueryBuilder[model.Blog]
+	blogService         blog.Service
+	eventService        event.Service
+	cacheService        cache.Service
+	autocompleteService autocomplete.Service
+	revisionService     revision.Service
+	reviewService       review.Service
+	workflowService     workflow.Service
+	tagService          tag.Service
+	blogsService        blogs.Service
+	logger              *slog.Logger
 }
 
-func NewService(db mongo.Database, blogService blog.Service) Service {
+func NewService(
+	db mongo.Database,
+	blogService blog.Service,
+	eventService event.Service,
+	cacheService cache.Service,
+	autocompleteService autocomplete.Service,
+	revisionService revision.Service,
+	reviewService review.Service,
+	workflowService workflow.Service,
+	tagService tag.Service,
+	blogsService blogs.Service,
+	logger *slog.Logger,
+) Service {
 	return &service{
-		blogQueryBuilder: mongo.NewQueryBuilder[model.Blog](db, model.CollectionName),
-		blogService:      blogService,
+		blogQueryBuilder:    mongo.NewQueryBuilder[model.Blog](db, model.CollectionName),
+		blogService:         blogService,
+		eventService:        eventService,
+		cacheService:        cacheService,
+		autocompleteService: autocompleteService,
+		revisionService:     revisionService,
+		reviewService:       reviewService,
+		workflowService:     workflowService,
+		tagService:          tagService,
+		blogsService:        blogsService,
+		logger:              logger,
 	}
 }
 
-func (s *service) CreateBlog(b *dto.CreateBlog, author *message.User) (*dto.PrivateBlog, error) {
+func (s *service) CreateBlog(ctx context.Context, b *dto.CreateBlog, author *message.User) (*dto.PrivateBlog, error) {
 	b.Slug = utils.FormatEndpoint(b.Slug)
 
-	exists := s.blogService.BlogSlugExists(b.Slug)
+	exists := s.blogService.BlogSlugExists(ctx, b.Slug)
 	if exists {
 		return nil, network.NewBadRequestError("Blog with slug: "+b.Slug+" already exists", nil)
 	}
 
-	blog, err := model.NewBlog(b.Slug, b.Title, b.Description, b.DraftText, b.Tags, author)
+	tags, err := s.tagService.Resolve(ctx, b.Tags)
 	if err != nil {
 		return nil, err
 	}
 
-	created, err := s.blogQueryBuilder.SingleQuery().InsertAndRetrieveOne(blog)
+	blog, err := model.NewBlog(b.Slug, b.Title, b.Description, b.DraftText, tags, author)
+	if err != nil {
+		return nil, err
+	}
+
+	// the event is inserted with the blog
+	blog.ID = primitive.NewObjectID()
+	e := eventMsg.NewBlogEvent(blogv1.NATS_EVENT_BLOG_CREATED, blog.ID, blog.Slug, author.ID)
+	blog.PendingEvents = []*eventMsg.BlogEvent{e}
+
+	created, err := s.blogQueryBuilder.Query(ctx).InsertAndRetrieveOne(blog)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	s.eventService.Publish(ctx, e)
+
+	// the history starts with the created blog, a blog without it fails the
+	// request like the insert would
+	err = s.revisionService.Record(ctx, created, revisionModel.KindCreate, author.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -62,23 +143,39 @@ func (s *service) CreateBlog(b *dto.CreateBlog, author *message.User) (*dto.Priv
 	return dto.NewPrivateBlog(created, author)
 }
 
-func (s *service) UpdateBlog(b *dto.UpdateBlog, author *message.User) (*dto.PrivateBlog, error) {
-	filter := bson.M{"_id": b.ID, "author": author.ID, "status": true}
-	blog, err := s.blogQueryBuilder.SingleQuery().FindOne(filter, nil)
+func (s *service) UpdateBlog(ctx context.Context, b *dto.UpdateBlog, author *message.User) (*dto.PrivateBlog, error) {
+	return s.updateBlog(ctx, b, author, revisionModel.KindUpdate)
+}
+
+func (s *service) updateBlog(ctx context.Context, b *dto.UpdateBlog, author *message.User, kind revisionModel.Kind) (*dto.PrivateBlog, error) {
+	filter := bson.M{"_id": b.ID, "author": author.ID, "state": bson.M{"$ne": model.StateDeleted}}
+	blog, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, nil)
 	if err != nil {
 		return nil, network.NewNotFoundError("Blog with id: "+b.ID.Hex()+" does not exists", nil)
 	}
 
+	// without a version the update applies to whatever is stored
+	if b.Version != nil {
+		if blog.Version != *b.Version {
+			return nil, blogDto.NewVersionConflictError(blog.Version)
+		}
+		filter["version"] = *b.Version
+	}
+
 	updates := bson.M{}
+	e := eventMsg.NewBlogEvent(blogv1.NATS_EVENT_BLOG_UPDATED, blog.ID, blog.Slug, author.ID)
 
 	if b.Slug != nil {
 		slug := utils.FormatEndpoint(*b.Slug)
 		if slug != blog.Slug {
-			exists := s.blogService.BlogSlugExists(slug)
-			if exists {
+			exists := s.blogService.BlogSlugExists(ctx, slug)
+			// a blog may take back a slug it has had before
+			if exists && !s.blogService.IsPreviousSlug(ctx, blog.ID, slug) {
 				return nil, network.NewBadRequestError("Blog with slug: "+slug+" already exists", nil)
 			}
 			updates["slug"] = slug
+			e.Slug = slug
+			e.PreviousSlug = &blog.Slug
 		}
 	}
 
@@ -95,7 +192,11 @@ func (s *service) UpdateBlog(b *dto.UpdateBlog, author *message.User) (*dto.Priv
 	}
 
 	if b.Tags != nil {
-		updates["tags"] = *b.Tags
+		tags, err := s.tagService.Resolve(ctx, *b.Tags)
+		if err != nil {
+			return nil, err
+		}
+		updates["tags"] = tags
 	}
 
 	if b.ImgURL != nil {
@@ -105,73 +206,265 @@ func (s *service) UpdateBlog(b *dto.UpdateBlog, author *message.User) (*dto.Priv
 	updates["updatedBy"] = author.ID
 	updates["updatedAt"] = time.Now()
 
-	set := bson.M{"$set": updates}
-	_, err = s.blogQueryBuilder.SingleQuery().UpdateOne(filter, set)
+	set := bson.M{
+		"$set":  updates,
+		"$inc":  bson.M{"version": 1},
+		"$push": bson.M{"pendingEvents": e},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated model.Blog
+	err = s.blogQueryBuilder.GetCollection().FindOneAndUpdate(ctx, filter, set, opts).Decode(&updated)
+	if err == mongod.ErrNoDocuments && b.Version != nil {
+		return nil, s.versionConflict(ctx, b.ID)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return s.GetBlogById(blog.ID, author)
+	// the update is stored with its event, the steps after it do not fail it
+	ctx = context.WithoutCancel(ctx)
+	s.eventService.Publish(ctx, e)
+
+	if e.PreviousSlug != nil {
+		err = s.blogService.RecordSlugChange(ctx, blog.ID, blog.Slug, updated.Slug)
+		common.FollowUp(s.logger, "author: slug history", blog.ID, err)
+	}
+
+	// the request fails without the revision, once the caches are right
+	revisionErr := s.revisionService.Record(ctx, &updated, kind, author.ID)
+
+	// the public fields of a published blog are edited in place
+	err = s.cacheService.InvalidateBlog(ctx, blog.ID, blog.Slug, e.Slug)
+	common.FollowUp(s.logger, "author: cache invalidation", blog.ID, err)
+
+	if blog.State == model.StatePublished {
+		err = s.reindexBlog(ctx, blog, &updated)
+		common.FollowUp(s.logger, "author: autocomplete update", blog.ID, err)
+		err = s.tagService.Recount(ctx, slices.Concat(blog.Tags, updated.Tags)...)
+		common.FollowUp(s.logger, "author: tag recount", blog.ID, err)
+		err = s.blogsService.RefreshListings(ctx, blog.ID)
+		common.FollowUp(s.logger, "author: listings refresh", blog.ID, err)
+	}
+
+	if revisionErr != nil {
+		return nil, revisionErr
+	}
+
+	return dto.NewPrivateBlog(&updated, author)
 }
 
-func (s *service) DeactivateBlog(blogId primitive.ObjectID, author *message.User) error {
-	filter := bson.M{"_id": blogId, "author": author.ID, "status": true}
-	update := bson.M{"$set": bson.M{"status": false, "updatedBy": author.ID, "updatedAt": time.Now()}}
-	result, err := s.blogQueryBuilder.SingleQuery().UpdateOne(filter, update)
+func (s *service) DeactivateBlog(ctx context.Context, blogId primitive.ObjectID, author *message.User) error {
+	blog, err := s.moveAndPublish(ctx, blogId, workflowModel.ActionDelete, blogv1.NATS_EVENT_BLOG_DELETED, author)
 	if err != nil {
 		return err
 	}
 
-	if result.MatchedCount == 0 {
-		return network.NewNotFoundError("blog not found", nil)
+	ctx = context.WithoutCancel(ctx)
+	if blog.State == model.StatePublished {
+		err = s.autocompleteService.RemoveBlog(ctx, blog)
+		common.FollowUp(s.logger, "author: autocomplete update", blog.ID, err)
+		err = s.tagService.Recount(ctx, blog.Tags...)
+		common.FollowUp(s.logger, "author: tag recount", blog.ID, err)
+		err = s.blogsService.RefreshListings(ctx, blog.ID)
+		common.FollowUp(s.logger, "author: listings refresh", blog.ID, err)
 	}
 
+	err = s.cacheService.InvalidateBlog(ctx, blog.ID, blog.Slug)
+	common.FollowUp(s.logger, "author: cache invalidation", blog.ID, err)
 	return nil
 }
 
-func (s *service) BlogSubmission(blogId primitive.ObjectID, author *message.User, submit bool) error {
-	filter := bson.M{"_id": blogId, "author": author.ID, "status": true}
-	update := bson.M{"$set": bson.M{"submitted": submit, "updatedBy": author.ID, "updatedAt": time.Now()}}
-	result, err := s.blogQueryBuilder.SingleQuery().UpdateOne(filter, update)
+func (s *service) BlogSubmission(ctx context.Context, blogId primitive.ObjectID, author *message.User, submit bool) error {
+	action := workflowModel.ActionSubmit
+	eventType := blogv1.NATS_EVENT_BLOG_SUBMITTED
+	if !submit {
+		action = workflowModel.ActionWithdraw
+		eventType = blogv1.NATS_EVENT_BLOG_WITHDRAWN
+	}
+	_, err := s.moveAndPublish(ctx, blogId, action, eventType, author)
+	return err
+}
+
+// moveAndPublish applies the workflow action to a blog of the author, along
+// with its event, and returns the blog as it was before.
+func (s *service) moveAndPublish(ctx context.Context, blogId primitive.ObjectID, action workflowModel.Action, eventType string, author *message.User) (*model.Blog, error) {
+	filter := bson.M{"author": author.ID}
+	e := eventMsg.NewBlogEvent(eventType, blogId, "", author.ID)
+	blog, err := s.workflowService.Move(ctx, blogId, action, author, message.RoleCodeAuthor, filter, nil, e)
 	if err != nil {
+		return nil, err
+	}
+
+	s.eventService.Publish(ctx, e)
+	return blog, nil
+}
+
+// reindexBlog replaces the autocomplete entries of a published blog whose title
+// or tags have been edited, the old tags are dropped if no other blog uses them.
+func (s *service) reindexBlog(ctx context.Context, old *model.Blog, updated *model.Blog) error {
+	if err := s.autocompleteService.RemoveBlog(ctx, old); err != nil {
 		return err
 	}
+	return s.autocompleteService.IndexBlog(ctx, updated)
+}
 
-	if result.MatchedCount == 0 {
-		return network.NewNotFoundError("blog not found", nil)
+func (s *service) GetBlogById(ctx context.Context, id primitive.ObjectID, author *message.User) (*dto.PrivateBlog, error) {
+	filter := bson.M{"_id": id, "author": author.ID, "state": bson.M{"$ne": model.StateDeleted}}
+
+	blog, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	b, err := dto.NewPrivateBlog(blog, author)
+	if err != nil {
+		return nil, err
+	}
+
+	b.Reviews, err = s.reviewService.GetThread(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (s *service) GetPaginatedDrafts(ctx context.Context, author *message.User, query *blogDto.ListQuery) (*blogDto.Page[*blogDto.InfoBlog], error) {
+	filter := bson.M{"author": author.ID, "state": model.StateDraft}
+	return s.getPaginated(ctx, filter, query)
 }
 
-func (s *service) GetBlogById(id primitive.ObjectID, author *message.User) (*dto.PrivateBlog, error) {
-	filter := bson.M{"_id": id, "author": author.ID, "status": true}
+func (s *service) GetPaginatedPublished(ctx context.Context, author *message.User, query *blogDto.ListQuery) (*blogDto.Page[*blogDto.InfoBlog], error) {
+	filter := bson.M{"author": author.ID, "state": model.StatePublished}
+	return s.getPaginated(ctx, filter, query)
+}
 
-	blog, err := s.blogQueryBuilder.SingleQuery().FindOne(filter, nil)
+func (s *service) GetPaginatedSubmitted(ctx context.Context, author *message.User, query *blogDto.ListQuery) (*blogDto.Page[*blogDto.InfoBlog], error) {
+	filter := bson.M{"author": author.ID, "state": bson.M{"$in": bson.A{model.StateSubmitted, model.StateScheduled}}}
+	return s.getPaginated(ctx, filter, query)
+}
+
+func (s *service) GetDraftsByCursor(ctx context.Context, author *message.User, query *blogDto.CursorQuery) (*blogDto.CursorPage[*blogDto.InfoBlog], error) {
+	filter := bson.M{"author": author.ID, "state": model.StateDraft}
+	return s.getByCursor(ctx, filter, query)
+}
+
+func (s *service) GetPublishedByCursor(ctx context.Context, author *message.User, query *blogDto.CursorQuery) (*blogDto.CursorPage[*blogDto.InfoBlog], error) {
+	filter := bson.M{"author": author.ID, "state": model.StatePublished}
+	return s.getByCursor(ctx, filter, query)
+}
+
+func (s *service) GetSubmittedByCursor(ctx context.Context, author *message.User, query *blogDto.CursorQuery) (*blogDto.CursorPage[*blogDto.InfoBlog], error) {
+	filter := bson.M{"author": author.ID, "state": bson.M{"$in": bson.A{model.StateSubmitted, model.StateScheduled}}}
+	return s.getByCursor(ctx, filter, query)
+}
+
+func (s *service) GetRevisions(ctx context.Context, blogId primitive.ObjectID, author *message.User, p *coredto.Pagination) ([]*revisionDto.InfoRevision, error) {
+	if err := s.checkOwnership(ctx, blogId, author); err != nil {
+		return nil, err
+	}
+	return s.revisionService.GetPaginated(ctx, blogId, p)
+}
+
+func (s *service) GetRevision(ctx context.Context, blogId primitive.ObjectID, number int64, author *message.User) (*revisionDto.Revision, error) {
+	if err := s.checkOwnership(ctx, blogId, author); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.revisionService.GetRevision(ctx, blogId, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisionDto.NewRevision(revision)
+}
+
+func (s *service) DiffRevisions(ctx context.Context, blogId primitive.ObjectID, query *revisionDto.DiffQuery, author *message.User) (*revisionDto.RevisionDiff, error) {
+	if err := s.checkOwnership(ctx, blogId, author); err != nil {
+		return nil, err
+	}
+	return s.revisionService.Diff(ctx, blogId, query)
+}
+
+// RestoreRevision copies the revision into the draft as a new revision. The
+// slug is kept, so that the links to the blog do not move again, and the draft
+// must still be at the version that the author restored it over.
+func (s *service) RestoreRevision(ctx context.Context, blogId primitive.ObjectID, number int64, version int64, author *message.User) (*dto.PrivateBlog, error) {
+	if err := s.checkOwnership(ctx, blogId, author); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.revisionService.GetRevision(ctx, blogId, number)
 	if err != nil {
 		return nil, err
 	}
 
-	return dto.NewPrivateBlog(blog, author)
+	update := &dto.UpdateBlog{
+		ID:          blogId,
+		Title:       &revision.Title,
+		Description: &revision.Description,
+		DraftText:   &revision.DraftText,
+		Tags:        &revision.Tags,
+		ImgURL:      revision.ImgURL,
+		Version:     &version,
+	}
+
+	return s.updateBlog(ctx, update, author, revisionModel.KindRestore)
 }
 
-func (s *service) GetPaginatedDrafts(author *message.User, p *coredto.Pagination) ([]*blogDto.InfoBlog, error) {
-	filter := bson.M{"author": author.ID, "status": true, "drafted": true}
-	return s.getPaginated(filter, p, nil)
+// versionConflict reports the version that a conditional update has missed.
+func (s *service) versionConflict(ctx context.Context, blogId primitive.ObjectID) error {
+	opts := options.FindOne().SetProjection(bson.D{{Key: "version", Value: 1}})
+	blog, err := s.blogQueryBuilder.Query(ctx).FindOne(bson.M{"_id": blogId}, opts)
+	if err != nil {
+		return network.NewNotFoundError("blog not found", err)
+	}
+	return blogDto.NewVersionConflictError(blog.Version)
+}
+
+func (s *service) checkOwnership(ctx context.Context, blogId primitive.ObjectID, author *message.User) error {
+	filter := bson.M{"_id": blogId, "author": author.ID, "state": bson.M{"$ne": model.StateDeleted}}
+	opts := options.FindOne().SetProjection(bson.D{{Key: "_id", Value: 1}})
+	_, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, opts)
+	if err != nil {
+		return network.NewNotFoundError("blog not found", err)
+	}
+	return nil
 }
 
-func (s *service) GetPaginatedPublished(author *message.User, p *coredto.Pagination) ([]*blogDto.InfoBlog, error) {
-	filter := bson.M{"author": author.ID, "status": true, "published": true}
-	return s.getPaginated(filter, p, nil)
+func (s *service) AddReview(ctx context.Context, blogId primitive.ObjectID, body *reviewDto.CreateReview, author *message.User) (*reviewDto.Review, error) {
+	if err := s.checkOwnership(ctx, blogId, author); err != nil {
+		return nil, err
+	}
+	return s.reviewService.AddComment(ctx, blogId, body, reviewModel.RoleAuthor, author.ID)
 }
 
-func (s *service) GetPaginatedSubmitted(author *message.User, p *coredto.Pagination) ([]*blogDto.InfoBlog, error) {
-	filter := bson.M{"author": author.ID, "status": true, "submitted": true}
-	return s.getPaginated(filter, p, nil)
+func (s *service) GetTransitions(ctx context.Context, blogId primitive.ObjectID, author *message.User) ([]*workflowDto.Transition, error) {
+	if err := s.checkOwnership(ctx, blogId, author); err != nil {
+		return nil, err
+	}
+	return s.workflowService.GetHistory(ctx, blogId)
 }
 
-func (s *service) getPaginated(filter bson.M, p *coredto.Pagination, opts *options.FindOptions) ([]*blogDto.InfoBlog, error) {
-	blogs, err := s.blogQueryBuilder.SingleQuery().FindPaginated(filter, p.Page, p.Limit, opts)
+func (s *service) getPaginated(ctx context.Context, filter bson.M, query *blogDto.ListQuery) (*blogDto.Page[*blogDto.InfoBlog], error) {
+	tags, err := s.tagService.Resolve(ctx, query.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err = query.Filter(filter, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.blogQueryBuilder.GetCollection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(query.SortOrder())
+	blogs, err := s.blogQueryBuilder.Query(ctx).FindPaginated(filter, query.Page, query.Limit, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -186,5 +479,24 @@ func (s *service) getPaginated(filter bson.M, p *coredto.Pagination, opts *optio
 		dtos[i] = d
 	}
 
-	return dtos, nil
+	return blogDto.NewPage(dtos, total, query.Page, query.Limit), nil
+}
+
+func (s *service) getByCursor(ctx context.Context, filter bson.M, query *blogDto.CursorQuery) (*blogDto.CursorPage[*blogDto.InfoBlog], error) {
+	tags, err := s.tagService.Resolve(ctx, query.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err = query.Filter(filter, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	blogs, next, err := common.FindByCursor(ctx, s.blogQueryBuilder, common.UpdatedOrder, filter, query.Cursor, query.Limit, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return blogDto.NewCursorPage(blogs, next, blogDto.NewInfoBlog)
 }
@@ -0,0 +1,47 @@
+package autocomplete
+
+import (
+	"github.com/afteracademy/gomicro/blog-service/api/autocomplete/dto"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+)
+
+type controller struct {
+	micro.Controller
+	service Service
+}
+
+func NewController(
+	authMFunc network.AuthenticationProvider,
+	authorizeMFunc network.AuthorizationProvider,
+	service Service,
+) micro.Controller {
+	return &controller{
+		Controller: micro.NewController("/suggest", authMFunc, authorizeMFunc),
+		service:    service,
+	}
+}
+
+func (c *controller) MountNats(group micro.NatsGroup) {}
+
+func (c *controller) MountRoutes(group *gin.RouterGroup) {
+	group.GET("", c.getSuggestionsHandler)
+}
+
+func (c *controller) getSuggestionsHandler(ctx *gin.Context) {
+	query, err := network.ReqQuery[dto.SuggestQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	suggestions, err := c.service.Suggest(ctx.Request.Context(), query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", suggestions)
+}
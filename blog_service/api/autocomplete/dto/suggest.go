@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+type SuggestQuery struct {
+	Prefix string `form:"q" binding:"required" validate:"required,min=1,max=50"`
+	Limit  int64  `form:"limit" validate:"omitempty,min=1,max=20"`
+}
+
+func EmptySuggestQuery() *SuggestQuery {
+	return &SuggestQuery{}
+}
+
+func (d *SuggestQuery) GetValue() *SuggestQuery {
+	return d
+}
+
+func (d *SuggestQuery) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
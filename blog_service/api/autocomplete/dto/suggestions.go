@@ -0,0 +1,11 @@
+package dto
+
+type TitleSuggestion struct {
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+type Suggestions struct {
+	Titles []*TitleSuggestion `json:"titles"`
+	Tags   []string           `json:"tags"`
+}
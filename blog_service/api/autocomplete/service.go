@@ -0,0 +1,349 @@
+package autocomplete
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/autocomplete/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/afteracademy/goserve/v2/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The titles and tags of the published blogs are kept in redis sorted sets
+// with equal scores, so that a prefix lookup is a ZRANGEBYLEX. A title member
+// is "<lowercase title>\x00<title>\x00<slug>", the hash maps a blog id to its
+// member, so that the member can be removed after the title has changed.
+const (
+	titlesKey       = "autocomplete_titles"
+	titleMembersKey = "autocomplete_title_members"
+	tagsKey         = "autocomplete_tags"
+	rebuildSuffix   = "_rebuild"
+	defaultLimit    = 8
+	separator       = "\x00"
+)
+
+// While a rebuild runs, the blogs indexed or removed meanwhile are listed, so
+// that the rebuild applies them again before it takes the place of the index.
+// The marker expires in case the rebuild dies.
+const (
+	rebuildingKey    = "autocomplete_rebuilding"
+	rebuildDirtyKey  = "autocomplete_rebuild_dirty"
+	rebuildMarkerTTL = 10 * time.Minute
+	rebuildRounds    = 5
+)
+
+type Service interface {
+	Suggest(ctx context.Context, query *dto.SuggestQuery) (*dto.Suggestions, error)
+	IndexBlog(ctx context.Context, blog *model.Blog) error
+	RemoveBlog(ctx context.Context, blog *model.Blog) error
+	Rebuild(ctx context.Context) (int, error)
+}
+
+type service struct {
+	blogQueryBuilder mongo.QueryBuilder[model.Blog]
+	store            redis.Store
+}
+
+func NewService(db mongo.Database, store redis.Store) Service {
+	return &service{
+		blogQueryBuilder: mongo.NewQueryBuilder[model.Blog](db, model.CollectionName),
+		store:            store,
+	}
+}
+
+func (s *service) Suggest(ctx context.Context, query *dto.SuggestQuery) (*dto.Suggestions, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultLimit
+	}
+
+	client := s.store.GetInstance()
+
+	prefix := strings.ToLower(strings.TrimSpace(query.Prefix))
+	// a blank prefix would match the whole index
+	if prefix == "" {
+		return nil, network.NewBadRequestError("q must not be blank", nil)
+	}
+
+	members, err := client.ZRangeByLex(ctx, titlesKey, lexRange(prefix, limit)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	tagPrefix := strings.ToUpper(strings.TrimSpace(query.Prefix))
+	tags, err := client.ZRangeByLex(ctx, tagsKey, lexRange(tagPrefix, limit)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := &dto.Suggestions{
+		Titles: make([]*dto.TitleSuggestion, 0, len(members)),
+		Tags:   tags,
+	}
+
+	for _, member := range members {
+		parts := strings.SplitN(member, separator, 3)
+		if len(parts) != 3 {
+			continue
+		}
+		suggestions.Titles = append(suggestions.Titles, &dto.TitleSuggestion{
+			Title: parts[1],
+			Slug:  parts[2],
+		})
+	}
+
+	return suggestions, nil
+}
+
+func (s *service) IndexBlog(ctx context.Context, blog *model.Blog) error {
+	client := s.store.GetInstance()
+	id := blog.ID.Hex()
+
+	previous, err := client.HGet(ctx, titleMembersKey, id).Result()
+	if err != nil && err != goredis.Nil {
+		return err
+	}
+
+	rebuilding, err := client.Exists(ctx, rebuildingKey).Result()
+	if err != nil {
+		return err
+	}
+
+	member := titleMember(blog)
+
+	pipe := client.TxPipeline()
+	if previous != "" && previous != member {
+		pipe.ZRem(ctx, titlesKey, previous)
+	}
+	pipe.ZAdd(ctx, titlesKey, goredis.Z{Member: member})
+	pipe.HSet(ctx, titleMembersKey, id, member)
+	for _, tag := range blog.Tags {
+		pipe.ZAdd(ctx, tagsKey, goredis.Z{Member: tag})
+	}
+	if rebuilding > 0 {
+		pipe.RPush(ctx, rebuildDirtyKey, id)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// RemoveBlog drops the title of the blog, and each of its tags that is no
+// longer used by another published blog. It must run after the blog has been
+// unpublished or deleted in the database.
+func (s *service) RemoveBlog(ctx context.Context, blog *model.Blog) error {
+	client := s.store.GetInstance()
+	id := blog.ID.Hex()
+
+	previous, err := client.HGet(ctx, titleMembersKey, id).Result()
+	if err != nil && err != goredis.Nil {
+		return err
+	}
+
+	var unused []any
+	for _, tag := range blog.Tags {
+		filter := bson.M{"tags": tag, "state": model.StatePublished}
+		count, err := s.blogQueryBuilder.GetCollection().CountDocuments(ctx, filter, options.Count().SetLimit(1))
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			unused = append(unused, tag)
+		}
+	}
+
+	rebuilding, err := client.Exists(ctx, rebuildingKey).Result()
+	if err != nil {
+		return err
+	}
+
+	pipe := client.TxPipeline()
+	if previous != "" {
+		pipe.ZRem(ctx, titlesKey, previous)
+	}
+	pipe.HDel(ctx, titleMembersKey, id)
+	if len(unused) > 0 {
+		pipe.ZRem(ctx, tagsKey, unused...)
+	}
+	if rebuilding > 0 {
+		pipe.RPush(ctx, rebuildDirtyKey, id)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Rebuild creates the index from the published blogs in mongo. It is built
+// under temporary keys and renamed in place, so that lookups never see a
+// partial index. It is safe while the blogs change, e.g. after a tag merge,
+// the changes made during the rebuild are applied to it before the rename.
+func (s *service) Rebuild(ctx context.Context) (int, error) {
+	client := s.store.GetInstance()
+
+	keys := []string{titlesKey + rebuildSuffix, titleMembersKey + rebuildSuffix, tagsKey + rebuildSuffix}
+	if err := client.Del(ctx, append(keys, rebuildDirtyKey)...).Err(); err != nil {
+		return 0, err
+	}
+	if err := client.Set(ctx, rebuildingKey, 1, rebuildMarkerTTL).Err(); err != nil {
+		return 0, err
+	}
+	defer client.Del(context.WithoutCancel(ctx), rebuildingKey)
+
+	filter := bson.M{"state": model.StatePublished}
+	projection := bson.D{{Key: "title", Value: 1}, {Key: "slug", Value: 1}}
+	cursor, err := s.blogQueryBuilder.GetCollection().Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var blog model.Blog
+		if err := cursor.Decode(&blog); err != nil {
+			return 0, err
+		}
+
+		member := titleMember(&blog)
+		pipe := client.Pipeline()
+		pipe.ZAdd(ctx, keys[0], goredis.Z{Member: member})
+		pipe.HSet(ctx, keys[1], blog.ID.Hex(), member)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return 0, err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+
+	// a change listed after the watch has started fails the swap, which is
+	// tried again with that change
+	for range rebuildRounds {
+		var count int64
+		err = client.Watch(ctx, func(tx *goredis.Tx) error {
+			var err error
+			if count, err = s.applyDirty(ctx, tx, keys); err != nil {
+				return err
+			}
+
+			// renaming a key that was never created fails, so an empty part
+			// of the index is dropped instead
+			built := make([]int64, len(keys))
+			for i, key := range keys {
+				if built[i], err = tx.Exists(ctx, key).Result(); err != nil {
+					return err
+				}
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+				pipe.Del(ctx, rebuildDirtyKey)
+				for i, live := range []string{titlesKey, titleMembersKey, tagsKey} {
+					pipe.Del(ctx, live)
+					if built[i] > 0 {
+						pipe.Rename(ctx, keys[i], live)
+					}
+				}
+				return nil
+			})
+			return err
+		}, rebuildDirtyKey)
+		if err != goredis.TxFailedErr {
+			return int(count), err
+		}
+	}
+	return 0, err
+}
+
+// applyDirty indexes again the blogs changed since the rebuild has started,
+// and fills the tags from the blogs as they are now. It returns the count of
+// the indexed blogs.
+func (s *service) applyDirty(ctx context.Context, tx *goredis.Tx, keys []string) (int64, error) {
+	dirty, err := tx.LRange(ctx, rebuildDirtyKey, 0, -1).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(dirty) > 0 {
+		ids := make(bson.A, 0, len(dirty))
+		for _, hex := range dirty {
+			id, err := primitive.ObjectIDFromHex(hex)
+			if err != nil {
+				continue
+			}
+			previous, err := tx.HGet(ctx, keys[1], hex).Result()
+			if err != nil && err != goredis.Nil {
+				return 0, err
+			}
+			if previous != "" {
+				if err = tx.ZRem(ctx, keys[0], previous).Err(); err != nil {
+					return 0, err
+				}
+			}
+			if err = tx.HDel(ctx, keys[1], hex).Err(); err != nil {
+				return 0, err
+			}
+			ids = append(ids, id)
+		}
+
+		filter := bson.M{"_id": bson.M{"$in": ids}, "state": model.StatePublished}
+		projection := bson.D{{Key: "title", Value: 1}, {Key: "slug", Value: 1}}
+		blogs, err := s.blogQueryBuilder.GetCollection().Find(ctx, filter, options.Find().SetProjection(projection))
+		if err != nil {
+			return 0, err
+		}
+		defer blogs.Close(ctx)
+		for blogs.Next(ctx) {
+			var blog model.Blog
+			if err := blogs.Decode(&blog); err != nil {
+				return 0, err
+			}
+			member := titleMember(&blog)
+			if err := tx.ZAdd(ctx, keys[0], goredis.Z{Member: member}).Err(); err != nil {
+				return 0, err
+			}
+			if err := tx.HSet(ctx, keys[1], blog.ID.Hex(), member).Err(); err != nil {
+				return 0, err
+			}
+		}
+		if err := blogs.Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	// the tags of a blog before its change are not known, so the tags are
+	// taken in one go once the titles are complete
+	tags, err := s.blogQueryBuilder.GetCollection().Distinct(ctx, "tags", bson.M{"state": model.StatePublished})
+	if err != nil {
+		return 0, err
+	}
+	if err = tx.Del(ctx, keys[2]).Err(); err != nil {
+		return 0, err
+	}
+	if len(tags) > 0 {
+		members := make([]goredis.Z, len(tags))
+		for i, tag := range tags {
+			members[i] = goredis.Z{Member: tag}
+		}
+		if err = tx.ZAdd(ctx, keys[2], members...).Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	return tx.HLen(ctx, keys[1]).Result()
+}
+
+func titleMember(blog *model.Blog) string {
+	return strings.ToLower(blog.Title) + separator + blog.Title + separator + blog.Slug
+}
+
+func lexRange(prefix string, limit int64) *goredis.ZRangeBy {
+	return &goredis.ZRangeBy{
+		Min:   "[" + prefix,
+		Max:   "[" + prefix + "\xff",
+		Count: limit,
+	}
+}
@@ -1,6 +1,10 @@
 package blog
 
 import (
+	"net/http"
+	"net/url"
+
+	"github.com/afteracademy/gomicro/blog-service/common"
 	coredto "github.com/afteracademy/goserve/v2/dto"
 	"github.com/afteracademy/goserve/v2/micro"
 	"github.com/afteracademy/goserve/v2/network"
@@ -37,20 +41,14 @@ func (c *controller) getBlogByIdHandler(ctx *gin.Context) {
 		return
 	}
 
-	blog, err := c.service.GetBlogDtoCacheById(mongoId.ID)
-	if err == nil {
-		network.SendSuccessDataResponse(ctx, "success", blog)
-		return
-	}
-
-	blog, err = c.service.GetPublisedBlogById(mongoId.ID)
+	blog, err := c.service.GetPublisedBlogById(ctx.Request.Context(), mongoId.ID)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
+	c.service.CountView(ctx.Request.Context(), blog.ID, visitor(ctx))
 	network.SendSuccessDataResponse(ctx, "success", blog)
-	c.service.SetBlogDtoCacheById(blog)
 }
 
 func (c *controller) getBlogBySlugHandler(ctx *gin.Context) {
@@ -60,18 +58,26 @@ func (c *controller) getBlogBySlugHandler(ctx *gin.Context) {
 		return
 	}
 
-	blog, err := c.service.GetBlogDtoCacheBySlug(slug.Slug)
-	if err == nil {
-		network.SendSuccessDataResponse(ctx, "success", blog)
-		return
+	blog, err := c.service.GetPublishedBlogBySlug(ctx.Request.Context(), slug.Slug)
+	if common.IsNotFound(err) {
+		if current, e := c.service.FindCurrentSlug(ctx.Request.Context(), slug.Slug); e == nil {
+			// relative to /slug/:slug, so that it holds behind the gateway too
+			ctx.Redirect(http.StatusMovedPermanently, url.PathEscape(current))
+			return
+		}
 	}
-
-	blog, err = c.service.GetPublishedBlogBySlug(slug.Slug)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
+	c.service.CountView(ctx.Request.Context(), blog.ID, visitor(ctx))
 	network.SendSuccessDataResponse(ctx, "success", blog)
-	c.service.SetBlogDtoCacheBySlug(blog)
+}
+
+// visitor tells the readers apart for the view count without a login. The
+// client address is taken from X-Forwarded-For only when the request comes
+// through one of the trusted proxies, so a client can not pose as others.
+func visitor(ctx *gin.Context) string {
+	return ctx.ClientIP() + " " + ctx.Request.UserAgent()
 }
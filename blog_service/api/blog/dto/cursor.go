@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CursorQuery walks through a list of blogs in the order of the cursor, the
+// latest updated first, so it takes no other sort. It narrows the list like a
+// ListQuery, From and To bounding the updated date.
+type CursorQuery struct {
+	Cursor string `form:"cursor" validate:"omitempty,max=200"`
+	Limit  int64  `form:"limit" binding:"required" validate:"required,min=1,max=1000"`
+	Sort   string `form:"sort" validate:"omitempty,oneof=updated"`
+	ListFilter
+}
+
+func EmptyCursorQuery() *CursorQuery {
+	return &CursorQuery{}
+}
+
+func (d *CursorQuery) GetValue() *CursorQuery {
+	return d
+}
+
+func (d *CursorQuery) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
+
+func (d *CursorQuery) Filter(filter bson.M, tags []string) (bson.M, error) {
+	return d.ListFilter.Filter(filter, tags, "updatedAt")
+}
+
+// CursorPage is a page of a list, NextCursor asks for the page after it.
+type CursorPage[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"nextCursor,omitempty"`
+	HasMore    bool    `json:"hasMore"`
+}
+
+// NewCursorPage makes the page of the blogs that common.FindByCursor has read.
+func NewCursorPage[T any](blogs []*model.Blog, next *string, newItem func(*model.Blog) (T, error)) (*CursorPage[T], error) {
+	page := &CursorPage[T]{Items: make([]T, 0, len(blogs)), NextCursor: next, HasMore: next != nil}
+	for _, b := range blogs {
+		item, err := newItem(b)
+		if err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, item)
+	}
+	return page, nil
+}
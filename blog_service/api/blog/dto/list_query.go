@@ -0,0 +1,136 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	SortUpdated   = "updated"
+	SortPublished = "published"
+	SortScore     = "score"
+	SortTitle     = "title"
+)
+
+const (
+	MatchAll = "all"
+	MatchAny = "any"
+)
+
+// ListFilter narrows a list of blogs. Tags keeps the blogs that have all of
+// them, or any of them when Match is any. From and To bound a date of the
+// blogs, which one is up to the order of the list.
+type ListFilter struct {
+	Tags  []string   `form:"tags" validate:"omitempty,max=10,dive,min=1,max=100"`
+	Match string     `form:"match" validate:"omitempty,oneof=all any"`
+	From  *time.Time `form:"from" validate:"omitempty"`
+	To    *time.Time `form:"to" validate:"omitempty"`
+}
+
+// Filter narrows the filter of the list to the tags, once resolved by the tag
+// registry, and to the range of the date field.
+func (d *ListFilter) Filter(filter bson.M, tags []string, dateField string) (bson.M, error) {
+	and := bson.A{filter}
+
+	if len(tags) > 0 {
+		if d.Match == MatchAny {
+			and = append(and, bson.M{"tags": bson.M{"$in": tags}})
+		} else {
+			and = append(and, bson.M{"tags": bson.M{"$all": tags}})
+		}
+	}
+
+	if d.From != nil && d.To != nil && d.To.Before(*d.From) {
+		return nil, network.NewBadRequestError("to must not be before from", nil)
+	}
+
+	dates := bson.M{}
+	if d.From != nil {
+		dates["$gte"] = *d.From
+	}
+	if d.To != nil {
+		dates["$lte"] = *d.To
+	}
+	if len(dates) > 0 {
+		and = append(and, bson.M{dateField: dates})
+	}
+
+	if len(and) == 1 {
+		return filter, nil
+	}
+	return bson.M{"$and": and}, nil
+}
+
+// ListQuery pages through a list of blogs. Sort defaults to the latest
+// updated first. From and To bound the published date when sorted by it, and
+// the updated date otherwise.
+type ListQuery struct {
+	Page  int64  `form:"page" binding:"required" validate:"required,min=1,max=1000"`
+	Limit int64  `form:"limit" binding:"required" validate:"required,min=1,max=1000"`
+	Sort  string `form:"sort" validate:"omitempty,oneof=updated published score title"`
+	ListFilter
+}
+
+func EmptyListQuery() *ListQuery {
+	return &ListQuery{}
+}
+
+func (d *ListQuery) GetValue() *ListQuery {
+	return d
+}
+
+func (d *ListQuery) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
+
+func (d *ListQuery) SortOrder() bson.D {
+	switch d.Sort {
+	case SortPublished:
+		return bson.D{{Key: "publishedAt", Value: -1}, {Key: "_id", Value: -1}}
+	case SortScore:
+		return bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: -1}}
+	case SortTitle:
+		return bson.D{{Key: "title", Value: 1}, {Key: "_id", Value: 1}}
+	default:
+		return bson.D{{Key: "updatedAt", Value: -1}, {Key: "score", Value: -1}, {Key: "_id", Value: -1}}
+	}
+}
+
+// Filter narrows the filter of the list by the ListFilter, the dates being
+// those the list is sorted by.
+func (d *ListQuery) Filter(filter bson.M, tags []string) (bson.M, error) {
+	field := "updatedAt"
+	if d.Sort == SortPublished {
+		field = "publishedAt"
+	}
+	return d.ListFilter.Filter(filter, tags, field)
+}
+
+// Page is a page of a list with the count of what the whole list holds.
+type Page[T any] struct {
+	Items   []T   `json:"items"`
+	Total   int64 `json:"total"`
+	Page    int64 `json:"page"`
+	Limit   int64 `json:"limit"`
+	Pages   int64 `json:"pages"`
+	HasNext bool  `json:"hasNext"`
+}
+
+func NewPage[T any](items []T, total int64, page int64, limit int64) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	pages := (total + limit - 1) / limit
+	return &Page[T]{
+		Items:   items,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+		Pages:   pages,
+		HasNext: page < pages,
+	}
+}
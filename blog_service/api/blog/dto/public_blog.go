@@ -11,16 +11,20 @@ import (
 )
 
 type PublicBlog struct {
-	ID          primitive.ObjectID `json:"_id" binding:"required" validate:"required"`
-	Title       string             `json:"title" validate:"required,min=3,max=500"`
-	Description string             `json:"description" validate:"required,min=3,max=2000"`
-	Text        string             `json:"text" validate:"required,max=50000"`
-	Slug        string             `json:"slug" validate:"required,min=3,max=200"`
-	Author      *dto.InfoAuthor    `json:"author,omitempty" validate:"required,omitempty"`
-	ImgURL      *string            `json:"imgUrl,omitempty" validate:"omitempty,uri,max=200"`
-	Score       *float64           `json:"score,omitempty" validate:"omitempty,min=0,max=1"`
-	Tags        *[]string          `json:"tags,omitempty" validate:"omitempty,dive,uppercase"`
-	PublishedAt *time.Time         `json:"publishedAt,omitempty"`
+	ID           primitive.ObjectID `json:"_id" binding:"required" validate:"required"`
+	Title        string             `json:"title" validate:"required,min=3,max=500"`
+	Description  string             `json:"description" validate:"required,min=3,max=2000"`
+	Text         string             `json:"text" validate:"required,max=50000"`
+	Slug         string             `json:"slug" validate:"required,min=3,max=200"`
+	Author       *dto.InfoAuthor    `json:"author,omitempty" validate:"required,omitempty"`
+	ImgURL       *string            `json:"imgUrl,omitempty" validate:"omitempty,uri,max=200"`
+	Score        *float64           `json:"score,omitempty" validate:"omitempty,min=0,max=1"`
+	Tags         *[]string          `json:"tags,omitempty" validate:"omitempty,dive,uppercase"`
+	PublishedAt  *time.Time         `json:"publishedAt,omitempty"`
+	CommentCount int64              `json:"commentCount"`
+	LikeCount    int64              `json:"likeCount"`
+	ClapCount    int64              `json:"clapCount"`
+	ViewCount    int64              `json:"viewCount"`
 }
 
 func EmptyInfoPublicBlog() *PublicBlog {
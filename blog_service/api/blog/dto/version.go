@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"strconv"
+
+	"github.com/afteracademy/gomicro/blog-service/common"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+// BlogVersion is the body of an action that has nothing else to send than the
+// version of the blog it was decided on.
+type BlogVersion struct {
+	Version *int64 `json:"version,omitempty" validate:"omitempty,min=1"`
+}
+
+func EmptyBlogVersion() *BlogVersion {
+	return &BlogVersion{}
+}
+
+func (d *BlogVersion) GetValue() *BlogVersion {
+	return d
+}
+
+func (d *BlogVersion) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
+
+func NewVersionConflictError(current int64) network.ApiError {
+	message := "blog has been changed since, the current version is " + strconv.FormatInt(current, 10)
+	return common.NewConflictError(message, &BlogVersion{Version: &current})
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	eventMsg "github.com/afteracademy/gomicro/blog-service/api/event/message"
 	"github.com/afteracademy/goserve/v2/mongo"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -16,26 +17,36 @@ import (
 
 const CollectionName = "blogs"
 
+const (
+	textIndexName       = "blog_text_search"
+	legacyTextIndexName = "title_text_description_text"
+)
+
 type Blog struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty"`
-	Title       string             `bson:"title" validate:"required,max=500"`
-	Description string             `bson:"description" validate:"required,max=2000"`
-	Text        *string            `bson:"text,omitempty"`
-	DraftText   string             `bson:"draftText" validate:"required"`
-	Tags        []string           `bson:"tags" validate:"required"`
-	Author      uuid.UUID          `bson:"author" validate:"required"`
-	ImgURL      *string            `bson:"imgUrl,omitempty"`
-	Slug        string             `bson:"slug" validate:"required,min=3,max=200"`
-	Score       float64            `bson:"score" validate:"min=0,max=1"`
-	Submitted   bool               `bson:"submitted"`
-	Drafted     bool               `bson:"drafted"`
-	Published   bool               `bson:"published"`
-	Status      bool               `bson:"status"`
-	PublishedAt *time.Time         `bson:"publishedAt,omitempty"`
-	CreatedBy   uuid.UUID          `bson:"createdBy" validate:"required"`
-	UpdatedBy   uuid.UUID          `bson:"updatedBy" validate:"required"`
-	CreatedAt   time.Time          `bson:"createdAt" validate:"required"`
-	UpdatedAt   time.Time          `bson:"updatedAt" validate:"required"`
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	Title        string             `bson:"title" validate:"required,max=500"`
+	Description  string             `bson:"description" validate:"required,max=2000"`
+	Text         *string            `bson:"text,omitempty"`
+	DraftText    string             `bson:"draftText" validate:"required"`
+	Tags         []string           `bson:"tags" validate:"required"`
+	Author       uuid.UUID          `bson:"author" validate:"required"`
+	ImgURL       *string            `bson:"imgUrl,omitempty"`
+	Slug         string             `bson:"slug" validate:"required,min=3,max=200"`
+	Score        float64            `bson:"score" validate:"min=0,max=1"`
+	State        State              `bson:"state" validate:"required,oneof=DRAFT SUBMITTED SCHEDULED PUBLISHED DELETED"`
+	Version      int64              `bson:"version" validate:"required,min=1"`
+	CommentCount int64              `bson:"commentCount" validate:"min=0"`
+	LikeCount    int64              `bson:"likeCount" validate:"min=0"`
+	ClapCount    int64              `bson:"clapCount" validate:"min=0"`
+	ViewCount    int64              `bson:"viewCount" validate:"min=0"`
+	PublishedAt  *time.Time         `bson:"publishedAt,omitempty"`
+	CreatedBy    uuid.UUID          `bson:"createdBy" validate:"required"`
+	UpdatedBy    uuid.UUID          `bson:"updatedBy" validate:"required"`
+	CreatedAt    time.Time          `bson:"createdAt" validate:"required"`
+	UpdatedAt    time.Time          `bson:"updatedAt" validate:"required"`
+	// PendingEvents are written by the same update as the change they tell
+	// about, and stay until the outbox has taken them
+	PendingEvents []*eventMsg.BlogEvent `bson:"pendingEvents,omitempty"`
 }
 
 func NewBlog(slug, title, description, draftText string, tags []string, author *message.User) (*Blog, error) {
@@ -48,10 +59,8 @@ func NewBlog(slug, title, description, draftText string, tags []string, author *
 		Author:      author.ID,
 		Slug:        slug,
 		Score:       0.01,
-		Submitted:   false,
-		Drafted:     true,
-		Published:   false,
-		Status:      true,
+		State:       StateDraft,
+		Version:     1,
 		CreatedBy:   author.ID,
 		UpdatedBy:   author.ID,
 		CreatedAt:   now,
@@ -77,18 +86,33 @@ func (*Blog) EnsureIndexes(db mongo.Database) {
 			Options: options.Index().SetUnique(true),
 		},
 		{
-			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
-			Options: options.Index().SetWeights(bson.M{
-				"title":       3,
-				"description": 1,
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}, {Key: "text", Value: "text"}},
+			Options: options.Index().SetName(textIndexName).SetWeights(bson.M{
+				"title":       5,
+				"description": 2,
+				"text":        1,
 			}),
 		},
-		{Keys: bson.D{{Key: "_id", Value: 1}, {Key: "status", Value: 1}}},
-		{Keys: bson.D{{Key: "published", Value: 1}, {Key: "status", Value: 1}}},
-		{Keys: bson.D{{Key: "_id", Value: 1}, {Key: "published", Value: 1}, {Key: "status", Value: 1}}},
-		{Keys: bson.D{{Key: "slug", Value: 1}, {Key: "published", Value: 1}, {Key: "status", Value: 1}}},
-		{Keys: bson.D{{Key: "tags", Value: 1}, {Key: "published", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "_id", Value: 1}, {Key: "state", Value: 1}}},
+		{Keys: bson.D{{Key: "state", Value: 1}}},
+		{Keys: bson.D{{Key: "author", Value: 1}, {Key: "state", Value: 1}}},
+		{Keys: bson.D{{Key: "slug", Value: 1}, {Key: "state", Value: 1}}},
+		{Keys: bson.D{{Key: "tags", Value: 1}, {Key: "state", Value: 1}}},
+		{Keys: bson.D{{Key: "state", Value: 1}, {Key: "publishedAt", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "state", Value: 1}, {Key: "updatedAt", Value: -1}, {Key: "score", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "state", Value: 1}, {Key: "score", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "state", Value: 1}, {Key: "title", Value: 1}, {Key: "_id", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "pendingEvents.occurredat", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
 	}
 
-	mongo.NewQueryBuilder[Blog](db, CollectionName).Query(context.Background()).CreateIndexes(indexes)
+	builder := mongo.NewQueryBuilder[Blog](db, CollectionName)
+
+	// a collection can only have one text index, the one without the text
+	// field has to go before the current one can be created
+	builder.GetCollection().Indexes().DropOne(context.Background(), legacyTextIndexName)
+
+	builder.Query(context.Background()).CreateIndexes(indexes)
 }
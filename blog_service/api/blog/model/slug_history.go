@@ -0,0 +1,53 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const SlugHistoryCollectionName = "blog_slug_history"
+
+// SlugHistory is a slug that a blog has given up. It stays reserved for the
+// blog, so that the links to it can be redirected to the current slug.
+type SlugHistory struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Slug      string             `bson:"slug" validate:"required,min=3,max=200"`
+	BlogID    primitive.ObjectID `bson:"blogId" validate:"required"`
+	CreatedAt time.Time          `bson:"createdAt" validate:"required"`
+}
+
+func NewSlugHistory(slug string, blogId primitive.ObjectID) (*SlugHistory, error) {
+	h := SlugHistory{
+		Slug:      slug,
+		BlogID:    blogId,
+		CreatedAt: time.Now(),
+	}
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (history *SlugHistory) Validate() error {
+	validate := validator.New()
+	return validate.Struct(history)
+}
+
+func (*SlugHistory) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{
+			Keys:    bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "blogId", Value: 1}}},
+	}
+
+	mongo.NewQueryBuilder[SlugHistory](db, SlugHistoryCollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
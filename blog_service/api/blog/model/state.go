@@ -0,0 +1,13 @@
+package model
+
+// State is where a blog is in the editorial workflow. It only changes through
+// the moves of the workflow package.
+type State string
+
+const (
+	StateDraft     State = "DRAFT"
+	StateSubmitted State = "SUBMITTED"
+	StateScheduled State = "SCHEDULED"
+	StatePublished State = "PUBLISHED"
+	StateDeleted   State = "DELETED"
+)
@@ -1,93 +1,181 @@
 package blog
 
 import (
+	"context"
+	"log/slog"
 	"time"
 
 	"github.com/afteracademy/gomicro/blog-service/api/auth"
 	"github.com/afteracademy/gomicro/blog-service/api/blog/dto"
 	"github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/gomicro/blog-service/api/view"
+	"github.com/afteracademy/gomicro/blog-service/common"
 	coredto "github.com/afteracademy/goserve/v2/dto"
 	"github.com/afteracademy/goserve/v2/mongo"
 	"github.com/afteracademy/goserve/v2/network"
 	"github.com/afteracademy/goserve/v2/redis"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type Service interface {
-	SetBlogDtoCacheById(blog *dto.PublicBlog) error
-	GetBlogDtoCacheById(id primitive.ObjectID) (*dto.PublicBlog, error)
-	SetBlogDtoCacheBySlug(blog *dto.PublicBlog) error
-	GetBlogDtoCacheBySlug(slug string) (*dto.PublicBlog, error)
-	BlogSlugExists(slug string) bool
-	GetPublisedBlogById(id primitive.ObjectID) (*dto.PublicBlog, error)
-	GetPublishedBlogBySlug(slug string) (*dto.PublicBlog, error)
-	getPublicPublishedBlog(filter bson.M) (*dto.PublicBlog, error)
-	getPaginated(filter bson.M, p *coredto.Pagination, opts *options.FindOptions) ([]*dto.InfoBlog, error)
+	DeleteBlogDtoCache(ctx context.Context, id primitive.ObjectID, slugs ...string) error
+	BlogSlugExists(ctx context.Context, slug string) bool
+	IsPreviousSlug(ctx context.Context, blogId primitive.ObjectID, slug string) bool
+	RecordSlugChange(ctx context.Context, blogId primitive.ObjectID, previous string, current string) error
+	FindCurrentSlug(ctx context.Context, slug string) (string, error)
+	GetPublisedBlogById(ctx context.Context, id primitive.ObjectID) (*dto.PublicBlog, error)
+	GetPublishedBlogBySlug(ctx context.Context, slug string) (*dto.PublicBlog, error)
+	CheckPublished(ctx context.Context, id primitive.ObjectID) error
+	CountView(ctx context.Context, id primitive.ObjectID, visitor string)
+	getPublicPublishedBlog(ctx context.Context, filter bson.M) (*dto.PublicBlog, error)
+	getPaginated(ctx context.Context, filter bson.M, p *coredto.Pagination, opts *options.FindOptions) ([]*dto.InfoBlog, error)
 }
 
 type service struct {
-	blogQueryBuilder mongo.QueryBuilder[model.Blog]
-	publicBlogCache  redis.Cache[dto.PublicBlog]
-	authService      auth.Service
+	blogQueryBuilder        mongo.QueryBuilder[model.Blog]
+	slugHistoryQueryBuilder mongo.QueryBuilder[model.SlugHistory]
+	publicBlogCache         common.ReadThrough[*dto.PublicBlog]
+	store                   redis.Store
+	authService             auth.Service
+	viewService             view.Service
+	logger                  *slog.Logger
 }
 
-func NewService(db mongo.Database, store redis.Store, authService auth.Service) Service {
+func NewService(db mongo.Database, store redis.Store, authService auth.Service, viewService view.Service, logger *slog.Logger) Service {
 	return &service{
-		blogQueryBuilder: mongo.NewQueryBuilder[model.Blog](db, model.CollectionName),
-		publicBlogCache:  redis.NewCache[dto.PublicBlog](store),
-		authService:      authService,
+		blogQueryBuilder:        mongo.NewQueryBuilder[model.Blog](db, model.CollectionName),
+		slugHistoryQueryBuilder: mongo.NewQueryBuilder[model.SlugHistory](db, model.SlugHistoryCollectionName),
+		publicBlogCache: common.NewReadThrough[*dto.PublicBlog](store, common.ReadThroughConfig{
+			TTL:         10 * time.Minute,
+			StaleTTL:    time.Minute,
+			NegativeTTL: time.Minute,
+			LockTTL:     10 * time.Second,
+			LockWait:    3 * time.Second,
+		}),
+		store:       store,
+		authService: authService,
+		viewService: viewService,
+		logger:      logger,
 	}
 }
 
-func (s *service) SetBlogDtoCacheById(blog *dto.PublicBlog) error {
-	key := "blog_" + blog.ID.Hex()
-	return s.publicBlogCache.SetJSON(key, blog, time.Duration(10*time.Minute))
+// DeleteBlogDtoCache drops the blog cached under its id and under each of the
+// given slugs, which should include the old slug after a slug change.
+func (s *service) DeleteBlogDtoCache(ctx context.Context, id primitive.ObjectID, slugs ...string) error {
+	keys := []string{"blog_" + id.Hex()}
+	for _, slug := range slugs {
+		keys = append(keys, "blog_"+slug)
+	}
+	return s.store.GetInstance().Del(ctx, keys...).Err()
 }
 
-func (s *service) GetBlogDtoCacheById(id primitive.ObjectID) (*dto.PublicBlog, error) {
-	key := "blog_" + id.Hex()
-	return s.publicBlogCache.GetJSON(key)
+func (s *service) BlogSlugExists(ctx context.Context, slug string) bool {
+	filter := bson.M{"slug": slug}
+	projection := bson.D{{Key: "state", Value: 1}}
+	opts := options.FindOne().SetProjection(projection)
+	_, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, opts)
+	if err == nil {
+		return true
+	}
+
+	// a previous slug stays taken, its links redirect to the blog
+	_, err = s.slugHistoryQueryBuilder.Query(ctx).FindOne(filter, nil)
+	return err == nil
 }
 
-func (s *service) SetBlogDtoCacheBySlug(blog *dto.PublicBlog) error {
-	key := "blog_" + blog.Slug
-	return s.publicBlogCache.SetJSON(key, blog, time.Duration(10*time.Minute))
+func (s *service) IsPreviousSlug(ctx context.Context, blogId primitive.ObjectID, slug string) bool {
+	filter := bson.M{"slug": slug, "blogId": blogId}
+	_, err := s.slugHistoryQueryBuilder.Query(ctx).FindOne(filter, nil)
+	return err == nil
 }
 
-func (s *service) GetBlogDtoCacheBySlug(slug string) (*dto.PublicBlog, error) {
-	key := "blog_" + slug
-	return s.publicBlogCache.GetJSON(key)
+// RecordSlugChange keeps the previous slug of the blog, and releases the
+// current one from the history when the blog has taken back an old slug.
+func (s *service) RecordSlugChange(ctx context.Context, blogId primitive.ObjectID, previous string, current string) error {
+	history, err := model.NewSlugHistory(previous, blogId)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.slugHistoryQueryBuilder.Query(ctx).InsertOne(history)
+	if err != nil && !mongod.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	filter := bson.M{"slug": current, "blogId": blogId}
+	_, err = s.slugHistoryQueryBuilder.GetCollection().DeleteOne(ctx, filter)
+	return err
 }
 
-func (s *service) BlogSlugExists(slug string) bool {
-	filter := bson.M{"slug": slug}
-	projection := bson.D{{Key: "status", Value: 1}}
-	opts := options.FindOne().SetProjection(projection)
-	_, err := s.blogQueryBuilder.SingleQuery().FindOne(filter, opts)
-	return err == nil
+// FindCurrentSlug returns the slug that the published blog which once had the
+// slug goes by now.
+func (s *service) FindCurrentSlug(ctx context.Context, slug string) (string, error) {
+	history, err := s.slugHistoryQueryBuilder.Query(ctx).FindOne(bson.M{"slug": slug}, nil)
+	if err != nil {
+		return "", network.NewNotFoundError("blog not found", err)
+	}
+
+	filter := bson.M{"_id": history.BlogID, "state": model.StatePublished}
+	opts := options.FindOne().SetProjection(bson.D{{Key: "slug", Value: 1}})
+	blog, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, opts)
+	if err != nil {
+		return "", network.NewNotFoundError("blog not found", err)
+	}
+
+	return blog.Slug, nil
 }
 
-func (s *service) GetPublisedBlogById(id primitive.ObjectID) (*dto.PublicBlog, error) {
-	filter := bson.M{"_id": id, "published": true, "status": true}
-	return s.getPublicPublishedBlog(filter)
+func (s *service) GetPublisedBlogById(ctx context.Context, id primitive.ObjectID) (*dto.PublicBlog, error) {
+	key := "blog_" + id.Hex()
+	return s.publicBlogCache.Get(ctx, key, func(ctx context.Context) (*dto.PublicBlog, error) {
+		filter := bson.M{"_id": id, "state": model.StatePublished}
+		return s.getPublicPublishedBlog(ctx, filter)
+	})
 }
 
-func (s *service) GetPublishedBlogBySlug(slug string) (*dto.PublicBlog, error) {
-	filter := bson.M{"slug": slug, "published": true, "status": true}
-	return s.getPublicPublishedBlog(filter)
+func (s *service) GetPublishedBlogBySlug(ctx context.Context, slug string) (*dto.PublicBlog, error) {
+	key := "blog_" + slug
+	return s.publicBlogCache.Get(ctx, key, func(ctx context.Context) (*dto.PublicBlog, error) {
+		filter := bson.M{"slug": slug, "state": model.StatePublished}
+		return s.getPublicPublishedBlog(ctx, filter)
+	})
 }
 
-func (s *service) getPublicPublishedBlog(filter bson.M) (*dto.PublicBlog, error) {
+// CheckPublished guards the actions of readers on a blog, e.g. a comment or a
+// reaction, which only a published blog takes.
+func (s *service) CheckPublished(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "state": model.StatePublished}
+	opts := options.FindOne().SetProjection(bson.D{{Key: "_id", Value: 1}})
+	_, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, opts)
+	if err != nil {
+		return network.NewNotFoundError("blog "+id.Hex()+" not found", err)
+	}
+	return nil
+}
+
+// CountView is best effort, a view that can not be counted does not fail the
+// read of the blog.
+func (s *service) CountView(ctx context.Context, id primitive.ObjectID, visitor string) {
+	if err := s.viewService.CountView(ctx, id, visitor); err != nil {
+		s.logger.Warn("blog: view not counted", "blog", id.Hex(), "error", err)
+	}
+}
+
+func (s *service) getPublicPublishedBlog(ctx context.Context, filter bson.M) (*dto.PublicBlog, error) {
 	projection := bson.D{{Key: "draftText", Value: 0}}
 	opts := options.FindOne().SetProjection(projection)
-	blog, err := s.blogQueryBuilder.SingleQuery().FindOne(filter, opts)
+	blog, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, opts)
 	if err != nil {
 		return nil, network.NewNotFoundError("blog not found", err)
 	}
 
-	author, err := s.authService.FindUserPublicProfile(blog.Author)
+	author, err := s.authService.FindUserPublicProfile(ctx, blog.Author)
+	if common.IsServiceUnavailable(err) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, network.NewNotFoundError("author not found", err)
 	}
@@ -95,8 +183,8 @@ func (s *service) getPublicPublishedBlog(filter bson.M) (*dto.PublicBlog, error)
 	return dto.NewPublicBlog(blog, author)
 }
 
-func (s *service) getPaginated(filter bson.M, p *coredto.Pagination, opts *options.FindOptions) ([]*dto.InfoBlog, error) {
-	blogs, err := s.blogQueryBuilder.SingleQuery().FindPaginated(filter, p.Page, p.Limit, opts)
+func (s *service) getPaginated(ctx context.Context, filter bson.M, p *coredto.Pagination, opts *options.FindOptions) ([]*dto.InfoBlog, error) {
+	blogs, err := s.blogQueryBuilder.Query(ctx).FindPaginated(filter, p.Page, p.Limit, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -1,7 +1,10 @@
 package blogs
 
 import (
+	blogDto "github.com/afteracademy/gomicro/blog-service/api/blog/dto"
 	"github.com/afteracademy/gomicro/blog-service/api/blogs/dto"
+	tagDto "github.com/afteracademy/gomicro/blog-service/api/tag/dto"
+	"github.com/afteracademy/gomicro/blog-service/common"
 	coredto "github.com/afteracademy/goserve/v2/dto"
 	"github.com/afteracademy/goserve/v2/micro"
 	"github.com/afteracademy/goserve/v2/network"
@@ -10,6 +13,7 @@ import (
 
 type controller struct {
 	micro.Controller
+	common.ContextPayload
 	service Service
 }
 
@@ -19,8 +23,9 @@ func NewController(
 	service Service,
 ) micro.Controller {
 	return &controller{
-		Controller: micro.NewController("/list", authMFunc, authorizeMFunc),
-		service:    service,
+		Controller:     micro.NewController("/list", authMFunc, authorizeMFunc),
+		ContextPayload: common.NewContextPayload(),
+		service:        service,
 	}
 }
 
@@ -29,45 +34,113 @@ func (c *controller) MountNats(group micro.NatsGroup) {}
 func (c *controller) MountRoutes(group *gin.RouterGroup) {
 	group.GET("/latest", c.getLatestBlogsHandler)
 	group.GET("/tag/:tag", c.getTaggedBlogsHandler)
+	group.GET("/tags", c.getTagsHandler)
 	group.GET("/similar/id/:id", c.getSimilarBlogsHandler)
+	group.GET("/trending", c.getTrendingBlogsHandler)
+	group.GET("/feed", c.Authentication(), c.getFeedHandler)
+	group.GET("/search", c.searchBlogsHandler)
 }
 
 func (c *controller) getLatestBlogsHandler(ctx *gin.Context) {
-	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
+	if common.HasCursor(ctx) {
+		c.getLatestBlogsByCursorHandler(ctx)
+		return
+	}
+
+	query, err := network.ReqQuery[blogDto.ListQuery](ctx)
 	if err != nil {
 		network.SendBadRequestError(ctx, err.Error(), err)
 		return
 	}
 
-	blogs, err := c.service.GetPaginatedLatestBlogs(pagination)
+	blogs, err := c.service.GetPaginatedLatestBlogs(ctx.Request.Context(), query)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
-	network.SendSuccessDataResponse(ctx, "success", &blogs)
+	network.SendSuccessDataResponse(ctx, "success", blogs)
+}
+
+func (c *controller) getLatestBlogsByCursorHandler(ctx *gin.Context) {
+	query, err := network.ReqQuery[blogDto.CursorQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	page, err := c.service.GetLatestBlogsByCursor(ctx.Request.Context(), query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", page)
 }
 
 func (c *controller) getTaggedBlogsHandler(ctx *gin.Context) {
+	if common.HasCursor(ctx) {
+		c.getTaggedBlogsByCursorHandler(ctx)
+		return
+	}
+
 	tag, err := network.ReqParams[dto.Tag](ctx)
 	if err != nil {
 		network.SendBadRequestError(ctx, err.Error(), err)
 		return
 	}
 
-	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
+	query, err := network.ReqQuery[blogDto.ListQuery](ctx)
 	if err != nil {
 		network.SendBadRequestError(ctx, err.Error(), err)
 		return
 	}
 
-	blogs, err := c.service.GetPaginatedTaggedBlogs(tag.Tag, pagination)
+	blogs, err := c.service.GetPaginatedTaggedBlogs(ctx.Request.Context(), tag.Tag, query)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
-	network.SendSuccessDataResponse(ctx, "success", &blogs)
+	network.SendSuccessDataResponse(ctx, "success", blogs)
+}
+
+func (c *controller) getTaggedBlogsByCursorHandler(ctx *gin.Context) {
+	tag, err := network.ReqParams[dto.Tag](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	query, err := network.ReqQuery[blogDto.CursorQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	page, err := c.service.GetTaggedBlogsByCursor(ctx.Request.Context(), tag.Tag, query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", page)
+}
+
+func (c *controller) getTagsHandler(ctx *gin.Context) {
+	query, err := network.ReqQuery[tagDto.TagQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	tags, err := c.service.GetPaginatedTags(ctx.Request.Context(), query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", &tags)
 }
 
 func (c *controller) getSimilarBlogsHandler(ctx *gin.Context) {
@@ -77,18 +150,61 @@ func (c *controller) getSimilarBlogsHandler(ctx *gin.Context) {
 		return
 	}
 
-	blogs, err := c.service.GetSimilarBlogsDtoCache(mongoId.ID)
-	if err == nil {
-		network.SendSuccessDataResponse(ctx, "success", &blogs)
+	blogs, err := c.service.GetSimilarBlogs(ctx.Request.Context(), mongoId.ID)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", &blogs)
+}
+
+func (c *controller) searchBlogsHandler(ctx *gin.Context) {
+	query, err := network.ReqQuery[dto.SearchQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	blogs, err := c.service.SearchBlogs(ctx.Request.Context(), query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", &blogs)
+}
+
+func (c *controller) getTrendingBlogsHandler(ctx *gin.Context) {
+	query, err := network.ReqQuery[dto.TrendingQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
 		return
 	}
 
-	blogs, err = c.service.GetSimilarBlogs(mongoId.ID)
+	blogs, err := c.service.GetTrendingBlogs(ctx.Request.Context(), query)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
 	network.SendSuccessDataResponse(ctx, "success", &blogs)
-	c.service.SetSimilarBlogsDtoCache(mongoId.ID, blogs)
+}
+
+func (c *controller) getFeedHandler(ctx *gin.Context) {
+	query, err := network.ReqQuery[dto.FeedQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	feed, err := c.service.GetFeed(ctx.Request.Context(), user, query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", feed)
 }
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+type FeedQuery struct {
+	Cursor string `form:"cursor" validate:"omitempty,max=200"`
+	Limit  int64  `form:"limit" binding:"required" validate:"required,min=1,max=100"`
+}
+
+func EmptyFeedQuery() *FeedQuery {
+	return &FeedQuery{}
+}
+
+func (d *FeedQuery) GetValue() *FeedQuery {
+	return d
+}
+
+func (d *FeedQuery) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
+
+// Feed is a page of the feed, NextCursor asks for the page after it.
+type Feed struct {
+	Blogs      []*ItemBlog `json:"blogs"`
+	NextCursor *string     `json:"nextCursor,omitempty"`
+	HasMore    bool        `json:"hasMore"`
+}
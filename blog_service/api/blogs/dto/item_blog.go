@@ -1,19 +1,29 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/afteracademy/gomicro/blog-service/api/blog/model"
 	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ItemBlog struct {
-	ID          primitive.ObjectID `json:"_id" binding:"required" validate:"required"`
-	Title       string             `json:"title" validate:"required,min=3,max=500"`
-	Description string             `json:"description" validate:"required,min=3,max=2000"`
-	Slug        string             `json:"slug" validate:"required,min=3,max=200"`
-	ImgURL      *string            `json:"imgUrl,omitempty" validate:"omitempty,uri,max=200"`
-	Score       float64            `json:"score," validate:"required,min=0,max=1"`
-	Tags        []string           `json:"tags" validate:"required,dive,uppercase"`
+	ID           primitive.ObjectID `json:"_id" binding:"required" validate:"required"`
+	Title        string             `json:"title" validate:"required,min=3,max=500"`
+	Description  string             `json:"description" validate:"required,min=3,max=2000"`
+	Slug         string             `json:"slug" validate:"required,min=3,max=200"`
+	ImgURL       *string            `json:"imgUrl,omitempty" validate:"omitempty,uri,max=200"`
+	Score        float64            `json:"score," validate:"required,min=0,max=1"`
+	Tags         []string           `json:"tags" validate:"required,dive,uppercase"`
+	CommentCount int64              `json:"commentCount"`
+	LikeCount    int64              `json:"likeCount"`
+	ClapCount    int64              `json:"clapCount"`
+	ViewCount    int64              `json:"viewCount"`
+	Author       uuid.UUID          `json:"author"`
+	PublishedAt  *time.Time         `json:"publishedAt,omitempty"`
+	UpdatedAt    time.Time          `json:"updatedAt"`
 }
 
 func NewItemBlog(blog *model.Blog) (*ItemBlog, error) {
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+type SearchQuery struct {
+	Query  string  `form:"q" binding:"required" validate:"required,min=2,max=100"`
+	Tag    *string `form:"tag" validate:"omitempty,max=100"`
+	Author *string `form:"author" validate:"omitempty,uuid"`
+	Page   int64   `form:"page" binding:"required" validate:"required,min=1,max=1000"`
+	Limit  int64   `form:"limit" binding:"required" validate:"required,min=1,max=100"`
+}
+
+func EmptySearchQuery() *SearchQuery {
+	return &SearchQuery{}
+}
+
+func (d *SearchQuery) GetValue() *SearchQuery {
+	return d
+}
+
+func (d *SearchQuery) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"github.com/afteracademy/gomicro/blog-service/api/blog/model"
+)
+
+type SearchBlog struct {
+	ItemBlog
+	Highlights []string `json:"highlights"`
+}
+
+func NewSearchBlog(blog *model.Blog, highlights []string) (*SearchBlog, error) {
+	item, err := NewItemBlog(blog)
+	if err != nil {
+		return nil, err
+	}
+	return &SearchBlog{
+		ItemBlog:   *item,
+		Highlights: highlights,
+	}, nil
+}
@@ -5,5 +5,5 @@ func EmptyTag() *Tag {
 }
 
 type Tag struct {
-	Tag string `uri:"tag" validate:"required,uppercase"`
+	Tag string `uri:"tag" validate:"required,max=100"`
 }
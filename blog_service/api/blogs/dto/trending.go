@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+type TrendingQuery struct {
+	Window string `form:"window" validate:"omitempty,max=10"`
+	Page   int64  `form:"page" binding:"required" validate:"required,min=1,max=1000"`
+	Limit  int64  `form:"limit" binding:"required" validate:"required,min=1,max=100"`
+}
+
+func EmptyTrendingQuery() *TrendingQuery {
+	return &TrendingQuery{}
+}
+
+func (d *TrendingQuery) GetValue() *TrendingQuery {
+	return d
+}
+
+func (d *TrendingQuery) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,92 @@
+package blogs
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	"github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/gomicro/blog-service/api/blogs/dto"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetFeed lists the published blogs of the followed authors and tags, the
+// latest first. The first page is cached per user until the user follows or
+// unfollows, or a blog of what the user follows changes. The pages after it
+// are read as they are asked for.
+func (s *service) GetFeed(ctx context.Context, user *message.User, query *dto.FeedQuery) (*dto.Feed, error) {
+	authors, tags, err := s.followService.GetFollowing(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Cursor != "" {
+		return s.findFeed(ctx, authors, tags, query)
+	}
+
+	generation := s.followService.GetFeedGeneration(ctx, user.ID, authors, tags)
+	key := "feed_" + user.ID.String() + "_" + generation + "_" + strconv.FormatInt(query.Limit, 10)
+	return s.feedCache.Get(ctx, key, func(ctx context.Context) (*dto.Feed, error) {
+		return s.findFeed(ctx, authors, tags, query)
+	})
+}
+
+func (s *service) findFeed(ctx context.Context, authors []uuid.UUID, tags []string, query *dto.FeedQuery) (*dto.Feed, error) {
+	feed := &dto.Feed{Blogs: []*dto.ItemBlog{}}
+	if len(authors) == 0 && len(tags) == 0 {
+		return feed, nil
+	}
+
+	filter := bson.M{
+		"state": model.StatePublished,
+		"$or": bson.A{
+			bson.M{"author": bson.M{"$in": authors}},
+			bson.M{"tags": bson.M{"$in": tags}},
+		},
+	}
+	opts := options.Find().SetProjection(bson.D{{Key: "draftText", Value: 0}, {Key: "text", Value: 0}})
+
+	blogs, next, err := common.FindByCursor(ctx, s.blogQueryBuilder, common.PublishedOrder, filter, query.Cursor, query.Limit, opts)
+	if err != nil {
+		return nil, err
+	}
+	feed.NextCursor = next
+	feed.HasMore = next != nil
+
+	for _, b := range blogs {
+		d, err := dto.NewItemBlog(b)
+		if err != nil {
+			return nil, err
+		}
+		feed.Blogs = append(feed.Blogs, d)
+	}
+
+	return feed, nil
+}
+
+// InvalidateFeeds drops the cached feeds of the users that follow the author
+// or a tag of the blog.
+func (s *service) InvalidateFeeds(ctx context.Context, blogId primitive.ObjectID) error {
+	opts := options.FindOne().SetProjection(bson.D{{Key: "author", Value: 1}, {Key: "tags", Value: 1}})
+	blog, err := s.blogQueryBuilder.Query(ctx).FindOne(bson.M{"_id": blogId}, opts)
+	if err != nil {
+		return err
+	}
+
+	return s.followService.InvalidateFeeds(ctx, blog.Author, blog.Tags)
+}
+
+// RefreshListings brings the feeds and the sitemaps, which are built from the
+// published blogs and shared by all the instances, up to a change of the
+// publication of the blog. Both are tried, the failures are returned together.
+func (s *service) RefreshListings(ctx context.Context, blogId primitive.ObjectID) error {
+	return errors.Join(
+		s.InvalidateFeeds(ctx, blogId),
+		s.sitemapService.Refresh(ctx, blogId),
+	)
+}
@@ -0,0 +1,88 @@
+package blogs
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	snippetBefore = 60
+	snippetAfter  = 120
+)
+
+// newHighlighter matches the terms of a text search query, ignoring the
+// negated terms and the quotes of phrases.
+func newHighlighter(query string) *regexp.Regexp {
+	var terms []string
+	for _, term := range strings.Fields(strings.ReplaceAll(query, `"`, " ")) {
+		if strings.HasPrefix(term, "-") || utf8.RuneCountInString(term) < 2 {
+			continue
+		}
+		terms = append(terms, regexp.QuoteMeta(term))
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)` + strings.Join(terms, "|"))
+}
+
+// highlight returns one snippet per field around the first match of the query,
+// with every match wrapped in <em>. The snippet is HTML, the text of the field
+// is escaped so that only the <em> markup gets through.
+func highlight(matcher *regexp.Regexp, fields ...string) []string {
+	snippets := []string{}
+	if matcher == nil {
+		return snippets
+	}
+
+	for _, field := range fields {
+		loc := matcher.FindStringIndex(field)
+		if loc == nil {
+			continue
+		}
+
+		start := runeStart(field, loc[0]-snippetBefore)
+		end := runeStart(field, loc[1]+snippetAfter)
+
+		snippet := mark(matcher, field[start:end])
+		if start > 0 {
+			snippet = "…" + snippet
+		}
+		if end < len(field) {
+			snippet = snippet + "…"
+		}
+		snippets = append(snippets, snippet)
+	}
+
+	return snippets
+}
+
+// mark escapes the text and wraps the escaped matches in <em>.
+func mark(matcher *regexp.Regexp, text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range matcher.FindAllStringIndex(text, -1) {
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(text[loc[0]:loc[1]]))
+		b.WriteString("</em>")
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+func runeStart(s string, i int) int {
+	if i <= 0 {
+		return 0
+	}
+	if i >= len(s) {
+		return len(s)
+	}
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
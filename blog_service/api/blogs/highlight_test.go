@@ -0,0 +1,23 @@
+package blogs
+
+import "testing"
+
+func TestHighlightEscapes(t *testing.T) {
+	matcher := newHighlighter("go")
+
+	got := highlight(matcher, `<script>alert("go")</script> & Go`)
+	want := `&lt;script&gt;alert(&#34;<em>go</em>&#34;)&lt;/script&gt; &amp; <em>Go</em>`
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestHighlightMatchesEscapedTerm(t *testing.T) {
+	matcher := newHighlighter("a&b")
+
+	got := highlight(matcher, "x a&b y")
+	want := "x <em>a&amp;b</em> y"
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -1,73 +1,166 @@
 package blogs
 
 import (
+	"context"
 	"time"
 
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	blogDto "github.com/afteracademy/gomicro/blog-service/api/blog/dto"
 	"github.com/afteracademy/gomicro/blog-service/api/blog/model"
 	"github.com/afteracademy/gomicro/blog-service/api/blogs/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/follow"
+	"github.com/afteracademy/gomicro/blog-service/api/sitemap"
+	"github.com/afteracademy/gomicro/blog-service/api/tag"
+	tagDto "github.com/afteracademy/gomicro/blog-service/api/tag/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/view"
+	"github.com/afteracademy/gomicro/blog-service/common"
 	coredto "github.com/afteracademy/goserve/v2/dto"
 	"github.com/afteracademy/goserve/v2/mongo"
 	"github.com/afteracademy/goserve/v2/network"
 	"github.com/afteracademy/goserve/v2/redis"
+	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const searchDecay = 30 * 24 * time.Hour
+
 type Service interface {
-	SetSimilarBlogsDtoCache(blogId primitive.ObjectID, blogs []*dto.ItemBlog) error
-	GetSimilarBlogsDtoCache(blogId primitive.ObjectID) ([]*dto.ItemBlog, error)
-	GetPaginatedLatestBlogs(p *coredto.Pagination) ([]*dto.ItemBlog, error)
-	GetPaginatedTaggedBlogs(tag string, p *coredto.Pagination) ([]*dto.ItemBlog, error)
-	GetSimilarBlogs(blogId primitive.ObjectID) ([]*dto.ItemBlog, error)
-	getPublicPaginated(filter bson.M, p *coredto.Pagination) ([]*dto.ItemBlog, error)
-	getPaginated(filter bson.M, p *coredto.Pagination, opts *options.FindOptions) ([]*dto.ItemBlog, error)
+	DeleteSimilarBlogsDtoCache(ctx context.Context, blogId primitive.ObjectID) error
+	GetPaginatedLatestBlogs(ctx context.Context, query *blogDto.ListQuery) (*blogDto.Page[*dto.ItemBlog], error)
+	GetPaginatedTaggedBlogs(ctx context.Context, tag string, query *blogDto.ListQuery) (*blogDto.Page[*dto.ItemBlog], error)
+	GetPaginatedAuthorBlogs(ctx context.Context, author uuid.UUID, query *blogDto.ListQuery) (*blogDto.Page[*dto.ItemBlog], error)
+	GetLatestBlogsByCursor(ctx context.Context, query *blogDto.CursorQuery) (*blogDto.CursorPage[*dto.ItemBlog], error)
+	GetTaggedBlogsByCursor(ctx context.Context, tag string, query *blogDto.CursorQuery) (*blogDto.CursorPage[*dto.ItemBlog], error)
+	GetSimilarBlogs(ctx context.Context, blogId primitive.ObjectID) ([]*dto.ItemBlog, error)
+	GetTrendingBlogs(ctx context.Context, query *dto.TrendingQuery) ([]*dto.ItemBlog, error)
+	GetFeed(ctx context.Context, user *message.User, query *dto.FeedQuery) (*dto.Feed, error)
+	InvalidateFeeds(ctx context.Context, blogId primitive.ObjectID) error
+	RefreshListings(ctx context.Context, blogId primitive.ObjectID) error
+	GetPaginatedTags(ctx context.Context, query *tagDto.TagQuery) ([]*tagDto.InfoTag, error)
+	SearchBlogs(ctx context.Context, query *dto.SearchQuery) ([]*dto.SearchBlog, error)
+	getPublicPaginated(ctx context.Context, filter bson.M, query *blogDto.ListQuery) (*blogDto.Page[*dto.ItemBlog], error)
+	getPaginated(ctx context.Context, filter bson.M, p *coredto.Pagination, opts *options.FindOptions) ([]*dto.ItemBlog, error)
+	getByCursor(ctx context.Context, filter bson.M, query *blogDto.CursorQuery) (*blogDto.CursorPage[*dto.ItemBlog], error)
 }
 
 type service struct {
 	blogQueryBuilder mongo.QueryBuilder[model.Blog]
-	itemBlogCache    redis.Cache[dto.ItemBlog]
+	similarCache     common.ReadThrough[[]*dto.ItemBlog]
+	feedCache        common.ReadThrough[*dto.Feed]
+	store            redis.Store
+	viewService      view.Service
+	followService    follow.Service
+	tagService       tag.Service
+	sitemapService   sitemap.Service
 }
 
-func NewService(db mongo.Database, store redis.Store) Service {
+func NewService(
+	db mongo.Database,
+	store redis.Store,
+	viewService view.Service,
+	followService follow.Service,
+	tagService tag.Service,
+	sitemapService sitemap.Service,
+) Service {
 	return &service{
 		blogQueryBuilder: mongo.NewQueryBuilder[model.Blog](db, model.CollectionName),
-		itemBlogCache:    redis.NewCache[dto.ItemBlog](store),
+		similarCache: common.NewReadThrough[[]*dto.ItemBlog](store, common.ReadThroughConfig{
+			TTL:         6 * time.Hour,
+			StaleTTL:    time.Hour,
+			NegativeTTL: time.Minute,
+			LockTTL:     10 * time.Second,
+			LockWait:    3 * time.Second,
+		}),
+		feedCache: common.NewReadThrough[*dto.Feed](store, common.ReadThroughConfig{
+			TTL:      5 * time.Minute,
+			LockTTL:  10 * time.Second,
+			LockWait: 3 * time.Second,
+		}),
+		store:          store,
+		viewService:    viewService,
+		followService:  followService,
+		tagService:     tagService,
+		sitemapService: sitemapService,
 	}
 }
 
-func (s *service) SetSimilarBlogsDtoCache(blogId primitive.ObjectID, blogs []*dto.ItemBlog) error {
-	key := "similar_blogs_" + blogId.Hex()
-	return s.itemBlogCache.SetJSONList(key, blogs, 6*time.Hour)
+// setSimilarBlogsRefs remembers which lists contain each blog, so that the
+// lists can be dropped together with the blog.
+func (s *service) setSimilarBlogsRefs(ctx context.Context, blogId primitive.ObjectID, blogs []*dto.ItemBlog) error {
+	pipe := s.store.GetInstance().Pipeline()
+	for _, b := range blogs {
+		refKey := "similar_blogs_refs_" + b.ID.Hex()
+		pipe.SAdd(ctx, refKey, blogId.Hex())
+		pipe.Expire(ctx, refKey, 7*time.Hour)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
 }
 
-func (s *service) GetSimilarBlogsDtoCache(blogId primitive.ObjectID) ([]*dto.ItemBlog, error) {
-	key := "similar_blogs_" + blogId.Hex()
-	return s.itemBlogCache.GetJSONList(key)
+// DeleteSimilarBlogsDtoCache drops the similar list of the blog and every
+// cached similar list of other blogs that contains it.
+func (s *service) DeleteSimilarBlogsDtoCache(ctx context.Context, blogId primitive.ObjectID) error {
+	client := s.store.GetInstance()
+	refKey := "similar_blogs_refs_" + blogId.Hex()
+
+	refs, err := client.SMembers(ctx, refKey).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{refKey, "similar_blogs_" + blogId.Hex()}
+	for _, ref := range refs {
+		keys = append(keys, "similar_blogs_"+ref)
+	}
+	return client.Del(ctx, keys...).Err()
+}
+
+func (s *service) GetPaginatedLatestBlogs(ctx context.Context, query *blogDto.ListQuery) (*blogDto.Page[*dto.ItemBlog], error) {
+	filter := bson.M{"state": model.StatePublished}
+	return s.getPublicPaginated(ctx, filter, query)
+}
+
+func (s *service) GetPaginatedTaggedBlogs(ctx context.Context, tag string, query *blogDto.ListQuery) (*blogDto.Page[*dto.ItemBlog], error) {
+	filter := bson.M{"state": model.StatePublished, "tags": s.tagService.ResolveOne(ctx, tag)}
+	return s.getPublicPaginated(ctx, filter, query)
+}
+
+func (s *service) GetPaginatedAuthorBlogs(ctx context.Context, author uuid.UUID, query *blogDto.ListQuery) (*blogDto.Page[*dto.ItemBlog], error) {
+	filter := bson.M{"state": model.StatePublished, "author": author}
+	return s.getPublicPaginated(ctx, filter, query)
 }
 
-func (s *service) GetPaginatedLatestBlogs(p *coredto.Pagination) ([]*dto.ItemBlog, error) {
-	filter := bson.M{"status": true, "published": true}
-	return s.getPublicPaginated(filter, p)
+func (s *service) GetLatestBlogsByCursor(ctx context.Context, query *blogDto.CursorQuery) (*blogDto.CursorPage[*dto.ItemBlog], error) {
+	filter := bson.M{"state": model.StatePublished}
+	return s.getByCursor(ctx, filter, query)
 }
 
-func (s *service) GetPaginatedTaggedBlogs(tag string, p *coredto.Pagination) ([]*dto.ItemBlog, error) {
-	filter := bson.M{"status": true, "published": true, "tags": tag}
-	return s.getPublicPaginated(filter, p)
+func (s *service) GetTaggedBlogsByCursor(ctx context.Context, tag string, query *blogDto.CursorQuery) (*blogDto.CursorPage[*dto.ItemBlog], error) {
+	filter := bson.M{"state": model.StatePublished, "tags": s.tagService.ResolveOne(ctx, tag)}
+	return s.getByCursor(ctx, filter, query)
 }
 
-func (s *service) GetSimilarBlogs(blogId primitive.ObjectID) ([]*dto.ItemBlog, error) {
-	filter := bson.M{"_id": blogId, "published": true, "status": true}
-	blog, err := s.blogQueryBuilder.SingleQuery().FindOne(filter, nil)
+func (s *service) GetSimilarBlogs(ctx context.Context, blogId primitive.ObjectID) ([]*dto.ItemBlog, error) {
+	key := "similar_blogs_" + blogId.Hex()
+	return s.similarCache.Get(ctx, key, func(ctx context.Context) ([]*dto.ItemBlog, error) {
+		return s.findSimilarBlogs(ctx, blogId)
+	})
+}
+
+func (s *service) findSimilarBlogs(ctx context.Context, blogId primitive.ObjectID) ([]*dto.ItemBlog, error) {
+	filter := bson.M{"_id": blogId, "state": model.StatePublished}
+	blog, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, nil)
 	if err != nil {
 		return nil, network.NewNotFoundError("blog not found", err)
 	}
 
 	filter = bson.M{
-		"$text":     bson.M{"$search": blog.Title, "$caseSensitive": false},
-		"status":    true,
-		"published": true,
-		"_id":       bson.M{"$ne": blog.ID},
+		"$text": bson.M{"$search": blog.Title, "$caseSensitive": false},
+		"state": model.StatePublished,
+		"_id":   bson.M{"$ne": blog.ID},
 	}
 
 	opts := options.Find()
@@ -83,18 +176,155 @@ func (s *service) GetSimilarBlogs(blogId primitive.ObjectID) ([]*dto.ItemBlog, e
 		Limit: 6,
 	}
 
-	return s.getPaginated(filter, pagination, opts)
+	blogs, err := s.getPaginated(ctx, filter, pagination, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.setSimilarBlogsRefs(ctx, blogId, blogs); err != nil {
+		return nil, err
+	}
+
+	return blogs, nil
+}
+
+// SearchBlogs ranks the published blogs by text score, boosted by the blog
+// score and decayed by age, so that a fresh and well scored match comes first.
+func (s *service) SearchBlogs(ctx context.Context, query *dto.SearchQuery) ([]*dto.SearchBlog, error) {
+	match := bson.M{
+		"$text": bson.M{"$search": query.Query, "$caseSensitive": false},
+		"state": model.StatePublished,
+	}
+
+	if query.Tag != nil {
+		match["tags"] = s.tagService.ResolveOne(ctx, *query.Tag)
+	}
+
+	if query.Author != nil {
+		author, err := uuid.Parse(*query.Author)
+		if err != nil {
+			return nil, network.NewBadRequestError("author is invalid", err)
+		}
+		match["author"] = author
+	}
+
+	// 1 / (1 + age / 30 days), i.e. half the rank after a month
+	age := bson.M{"$subtract": bson.A{"$$NOW", bson.M{"$ifNull": bson.A{"$publishedAt", "$createdAt"}}}}
+	recency := bson.M{"$divide": bson.A{1, bson.M{"$add": bson.A{1, bson.M{"$divide": bson.A{age, searchDecay.Milliseconds()}}}}}}
+	rank := bson.M{"$multiply": bson.A{bson.M{"$meta": "textScore"}, bson.M{"$add": bson.A{1, "$score"}}, recency}}
+
+	pipeline := mongod.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{"rank": rank}}},
+		{{Key: "$sort", Value: bson.D{{Key: "rank", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$skip", Value: (query.Page - 1) * query.Limit}},
+		{{Key: "$limit", Value: query.Limit}},
+		{{Key: "$project", Value: bson.M{"draftText": 0}}},
+	}
+
+	cursor, err := s.blogQueryBuilder.GetCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var blogs []*model.Blog
+	if err = cursor.All(ctx, &blogs); err != nil {
+		return nil, err
+	}
+
+	matcher := newHighlighter(query.Query)
+	dtos := make([]*dto.SearchBlog, len(blogs))
+
+	for i, b := range blogs {
+		fields := []string{b.Title, b.Description}
+		if b.Text != nil {
+			fields = append(fields, *b.Text)
+		}
+		d, err := dto.NewSearchBlog(b, highlight(matcher, fields...))
+		if err != nil {
+			return nil, err
+		}
+		dtos[i] = d
+	}
+
+	return dtos, nil
+}
+
+func (s *service) GetPaginatedTags(ctx context.Context, query *tagDto.TagQuery) ([]*tagDto.InfoTag, error) {
+	return s.tagService.GetPaginated(ctx, query)
+}
+
+// GetTrendingBlogs lists the published blogs with the most views in the
+// window, a blog that is no longer published is left out of its page.
+func (s *service) GetTrendingBlogs(ctx context.Context, query *dto.TrendingQuery) ([]*dto.ItemBlog, error) {
+	p := &coredto.Pagination{Page: query.Page, Limit: query.Limit}
+	ids, err := s.viewService.GetTrendingIds(ctx, query.Window, p)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []*dto.ItemBlog{}, nil
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": ids}, "state": model.StatePublished}
+	opts := options.Find().SetProjection(bson.D{{Key: "draftText", Value: 0}})
+	blogs, err := s.blogQueryBuilder.Query(ctx).FindAll(filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[primitive.ObjectID]*model.Blog, len(blogs))
+	for _, b := range blogs {
+		found[b.ID] = b
+	}
+
+	dtos := make([]*dto.ItemBlog, 0, len(blogs))
+	for _, id := range ids {
+		b, ok := found[id]
+		if !ok {
+			continue
+		}
+		d, err := dto.NewItemBlog(b)
+		if err != nil {
+			return nil, err
+		}
+		dtos = append(dtos, d)
+	}
+
+	return dtos, nil
 }
 
-func (s *service) getPublicPaginated(filter bson.M, p *coredto.Pagination) ([]*dto.ItemBlog, error) {
+func (s *service) getPublicPaginated(ctx context.Context, filter bson.M, query *blogDto.ListQuery) (*blogDto.Page[*dto.ItemBlog], error) {
+	tags, err := s.tagService.Resolve(ctx, query.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err = query.Filter(filter, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.blogQueryBuilder.GetCollection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
 	projection := bson.D{{Key: "draftText", Value: 0}}
 	opts := options.Find().SetProjection(projection)
-	opts.SetSort(bson.D{{Key: "updatedAt", Value: -1}, {Key: "score", Value: -1}})
-	return s.getPaginated(filter, p, opts)
+	opts.SetSort(query.SortOrder())
+
+	p := &coredto.Pagination{Page: query.Page, Limit: query.Limit}
+	blogs, err := s.getPaginated(ctx, filter, p, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return blogDto.NewPage(blogs, total, query.Page, query.Limit), nil
 }
 
-func (s *service) getPaginated(filter bson.M, p *coredto.Pagination, opts *options.FindOptions) ([]*dto.ItemBlog, error) {
-	blogs, err := s.blogQueryBuilder.SingleQuery().FindPaginated(filter, p.Page, p.Limit, opts)
+func (s *service) getPaginated(ctx context.Context, filter bson.M, p *coredto.Pagination, opts *options.FindOptions) ([]*dto.ItemBlog, error) {
+	blogs, err := s.blogQueryBuilder.Query(ctx).FindPaginated(filter, p.Page, p.Limit, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -111,3 +341,23 @@ func (s *service) getPaginated(filter bson.M, p *coredto.Pagination, opts *optio
 
 	return dtos, nil
 }
+
+func (s *service) getByCursor(ctx context.Context, filter bson.M, query *blogDto.CursorQuery) (*blogDto.CursorPage[*dto.ItemBlog], error) {
+	tags, err := s.tagService.Resolve(ctx, query.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err = query.Filter(filter, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetProjection(bson.D{{Key: "draftText", Value: 0}})
+	blogs, next, err := common.FindByCursor(ctx, s.blogQueryBuilder, common.UpdatedOrder, filter, query.Cursor, query.Limit, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return blogDto.NewCursorPage(blogs, next, dto.NewItemBlog)
+}
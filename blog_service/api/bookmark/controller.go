@@ -0,0 +1,346 @@
+package bookmark
+
+import (
+	"github.com/afteracademy/gomicro/blog-service/api/bookmark/dto"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	coredto "github.com/afteracademy/goserve/v2/dto"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+)
+
+type controller struct {
+	micro.Controller
+	common.ContextPayload
+	service Service
+}
+
+func NewController(
+	authMFunc network.AuthenticationProvider,
+	authorizeMFunc network.AuthorizationProvider,
+	service Service,
+) micro.Controller {
+	return &controller{
+		Controller:     micro.NewController("/bookmark", authMFunc, authorizeMFunc),
+		ContextPayload: common.NewContextPayload(),
+		service:        service,
+	}
+}
+
+func (c *controller) MountNats(group micro.NatsGroup) {}
+
+func (c *controller) MountRoutes(group *gin.RouterGroup) {
+	group.GET("/list/public/id/:id", c.getPublicReadingListHandler)
+	group.GET("/list/shared/:token", c.getSharedReadingListHandler)
+
+	private := group.Group("", c.Authentication())
+	private.GET("/blogs", c.getBookmarksHandler)
+	private.PUT("/blog/id/:id", c.addBookmarkHandler)
+	private.DELETE("/blog/id/:id", c.removeBookmarkHandler)
+	private.POST("/list", c.postReadingListHandler)
+	private.GET("/lists", c.getReadingListsHandler)
+	private.GET("/list/id/:id", c.getReadingListHandler)
+	private.PUT("/list/id/:id", c.updateReadingListHandler)
+	private.DELETE("/list/id/:id", c.deleteReadingListHandler)
+	private.PUT("/list/id/:id/blog/:blogId", c.addListBlogHandler)
+	private.DELETE("/list/id/:id/blog/:blogId", c.removeListBlogHandler)
+	private.PUT("/list/order/id/:id", c.orderReadingListHandler)
+	private.PUT("/list/share/id/:id", c.shareReadingListHandler)
+	private.DELETE("/list/share/id/:id", c.unshareReadingListHandler)
+}
+
+func (c *controller) getPublicReadingListHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	list, err := c.service.GetPublicReadingList(ctx.Request.Context(), mongoId.ID, pagination)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", list)
+}
+
+func (c *controller) getSharedReadingListHandler(ctx *gin.Context) {
+	token, err := network.ReqParams[dto.ShareToken](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	list, err := c.service.GetSharedReadingList(ctx.Request.Context(), token.Token, pagination)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", list)
+}
+
+func (c *controller) getBookmarksHandler(ctx *gin.Context) {
+	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	bookmarks, err := c.service.GetPaginatedBookmarks(ctx.Request.Context(), user, pagination)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", &bookmarks)
+}
+
+func (c *controller) addBookmarkHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	err = c.service.AddBookmark(ctx.Request.Context(), mongoId.ID, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessMsgResponse(ctx, "bookmark added successfully")
+}
+
+func (c *controller) removeBookmarkHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	err = c.service.RemoveBookmark(ctx.Request.Context(), mongoId.ID, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessMsgResponse(ctx, "bookmark removed successfully")
+}
+
+func (c *controller) postReadingListHandler(ctx *gin.Context) {
+	body, err := network.ReqBody[dto.CreateReadingList](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	list, err := c.service.CreateReadingList(ctx.Request.Context(), body, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "reading list created successfully", list)
+}
+
+func (c *controller) getReadingListsHandler(ctx *gin.Context) {
+	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	lists, err := c.service.GetPaginatedReadingLists(ctx.Request.Context(), user, pagination)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", &lists)
+}
+
+func (c *controller) getReadingListHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	list, err := c.service.GetReadingList(ctx.Request.Context(), mongoId.ID, user, pagination)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", list)
+}
+
+func (c *controller) updateReadingListHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	body, err := network.ReqBody[dto.UpdateReadingList](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	list, err := c.service.UpdateReadingList(ctx.Request.Context(), mongoId.ID, body, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "reading list updated successfully", list)
+}
+
+func (c *controller) deleteReadingListHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	err = c.service.DeleteReadingList(ctx.Request.Context(), mongoId.ID, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessMsgResponse(ctx, "reading list deleted successfully")
+}
+
+func (c *controller) addListBlogHandler(ctx *gin.Context) {
+	params, err := network.ReqParams[dto.ListBlog](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	list, err := c.service.AddListBlog(ctx.Request.Context(), params.ID, params.BlogID, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "blog added to reading list successfully", list)
+}
+
+func (c *controller) removeListBlogHandler(ctx *gin.Context) {
+	params, err := network.ReqParams[dto.ListBlog](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	list, err := c.service.RemoveListBlog(ctx.Request.Context(), params.ID, params.BlogID, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "blog removed from reading list successfully", list)
+}
+
+func (c *controller) orderReadingListHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	body, err := network.ReqBody[dto.OrderReadingList](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	list, err := c.service.OrderReadingList(ctx.Request.Context(), mongoId.ID, body, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "reading list ordered successfully", list)
+}
+
+func (c *controller) shareReadingListHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	list, err := c.service.ShareReadingList(ctx.Request.Context(), mongoId.ID, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "reading list shared successfully", list)
+}
+
+func (c *controller) unshareReadingListHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	list, err := c.service.UnshareReadingList(ctx.Request.Context(), mongoId.ID, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "reading list unshared successfully", list)
+}
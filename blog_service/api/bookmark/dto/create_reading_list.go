@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"github.com/afteracademy/gomicro/blog-service/api/bookmark/model"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+type CreateReadingList struct {
+	Name        string           `json:"name" validate:"required,min=1,max=100"`
+	Description *string          `json:"description,omitempty" validate:"omitempty,max=500"`
+	Visibility  model.Visibility `json:"visibility" validate:"omitempty,oneof=PRIVATE PUBLIC"`
+}
+
+func EmptyCreateReadingList() *CreateReadingList {
+	return &CreateReadingList{}
+}
+
+func (d *CreateReadingList) GetValue() *CreateReadingList {
+	if d.Visibility == "" {
+		d.Visibility = model.VisibilityPrivate
+	}
+	return d
+}
+
+func (d *CreateReadingList) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"time"
+
+	blogsDto "github.com/afteracademy/gomicro/blog-service/api/blogs/dto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Item is a blog in the bookmarks or a reading list. A blog that is no longer
+// published stays in place without its details, so the reader can remove it.
+type Item struct {
+	BlogID    primitive.ObjectID `json:"blogId"`
+	AddedAt   time.Time          `json:"addedAt"`
+	Available bool               `json:"available"`
+	Blog      *blogsDto.ItemBlog `json:"blog,omitempty"`
+}
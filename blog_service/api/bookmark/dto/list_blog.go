@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ListBlog struct {
+	Id     string             `uri:"id" binding:"required" validate:"required,len=24"`
+	BlogId string             `uri:"blogId" binding:"required" validate:"required,len=24"`
+	ID     primitive.ObjectID `uri:"-" validate:"-"`
+	BlogID primitive.ObjectID `uri:"-" validate:"-"`
+}
+
+func EmptyListBlog() *ListBlog {
+	return &ListBlog{}
+}
+
+func (d *ListBlog) GetValue() *ListBlog {
+	if id, err := mongo.NewObjectID(d.Id); err == nil {
+		d.ID = id
+	}
+	if id, err := mongo.NewObjectID(d.BlogId); err == nil {
+		d.BlogID = id
+	}
+	return d
+}
+
+func (d *ListBlog) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
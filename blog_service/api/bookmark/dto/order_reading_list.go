@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OrderReadingList lists every blog of the reading list in the new order.
+type OrderReadingList struct {
+	BlogIds []string             `json:"blogIds" validate:"required,max=500,dive,len=24"`
+	BlogIDs []primitive.ObjectID `json:"-" validate:"-"`
+}
+
+func EmptyOrderReadingList() *OrderReadingList {
+	return &OrderReadingList{}
+}
+
+func (d *OrderReadingList) GetValue() *OrderReadingList {
+	d.BlogIDs = make([]primitive.ObjectID, 0, len(d.BlogIds))
+	for _, hex := range d.BlogIds {
+		if id, err := mongo.NewObjectID(hex); err == nil {
+			d.BlogIDs = append(d.BlogIDs, id)
+		}
+	}
+	return d
+}
+
+func (d *OrderReadingList) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
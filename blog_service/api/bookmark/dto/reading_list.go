@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/bookmark/model"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InfoReadingList struct {
+	ID          primitive.ObjectID `json:"_id"`
+	Owner       uuid.UUID          `json:"owner"`
+	Name        string             `json:"name"`
+	Description *string            `json:"description,omitempty"`
+	Visibility  model.Visibility   `json:"visibility"`
+	ShareToken  *string            `json:"shareToken,omitempty"`
+	ItemCount   int                `json:"itemCount"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+}
+
+// NewInfoReadingList shows the share token to the owner only.
+func NewInfoReadingList(list *model.ReadingList, owner bool) *InfoReadingList {
+	d := &InfoReadingList{
+		ID:          list.ID,
+		Owner:       list.Owner,
+		Name:        list.Name,
+		Description: list.Description,
+		Visibility:  list.Visibility,
+		ItemCount:   len(list.Items),
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+	}
+	if owner {
+		d.ShareToken = list.ShareToken
+	}
+	return d
+}
+
+type ReadingList struct {
+	InfoReadingList
+	Items []*Item `json:"items"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+type ShareToken struct {
+	Token string `uri:"token" binding:"required" validate:"required,uuid"`
+}
+
+func EmptyShareToken() *ShareToken {
+	return &ShareToken{}
+}
+
+func (d *ShareToken) GetValue() *ShareToken {
+	return d
+}
+
+func (d *ShareToken) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"github.com/afteracademy/gomicro/blog-service/api/bookmark/model"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+type UpdateReadingList struct {
+	Name        *string           `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description *string           `json:"description,omitempty" validate:"omitempty,max=500"`
+	Visibility  *model.Visibility `json:"visibility,omitempty" validate:"omitempty,oneof=PRIVATE PUBLIC"`
+}
+
+func EmptyUpdateReadingList() *UpdateReadingList {
+	return &UpdateReadingList{}
+}
+
+func (d *UpdateReadingList) GetValue() *UpdateReadingList {
+	return d
+}
+
+func (d *UpdateReadingList) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
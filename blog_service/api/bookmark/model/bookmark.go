@@ -0,0 +1,52 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const BookmarkCollectionName = "bookmarks"
+
+type Bookmark struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	User      uuid.UUID          `bson:"user" validate:"required"`
+	BlogID    primitive.ObjectID `bson:"blogId" validate:"required"`
+	CreatedAt time.Time          `bson:"createdAt" validate:"required"`
+}
+
+func NewBookmark(user uuid.UUID, blogId primitive.ObjectID) (*Bookmark, error) {
+	b := Bookmark{
+		User:      user,
+		BlogID:    blogId,
+		CreatedAt: time.Now(),
+	}
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (bookmark *Bookmark) Validate() error {
+	validate := validator.New()
+	return validate.Struct(bookmark)
+}
+
+func (*Bookmark) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user", Value: 1}, {Key: "blogId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user", Value: 1}, {Key: "createdAt", Value: -1}}},
+	}
+
+	mongo.NewQueryBuilder[Bookmark](db, BookmarkCollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const ReadingListCollectionName = "reading_lists"
+
+// MaxListItems keeps a reading list within a reasonable document size
+const MaxListItems = 500
+
+type Visibility string
+
+const (
+	VisibilityPrivate Visibility = "PRIVATE"
+	VisibilityPublic  Visibility = "PUBLIC"
+)
+
+type Item struct {
+	BlogID  primitive.ObjectID `bson:"blogId" validate:"required"`
+	AddedAt time.Time          `bson:"addedAt" validate:"required"`
+}
+
+// ReadingList keeps its blogs in the order of Items. A public list can be read
+// by anyone, a private one only by its owner and by whoever has the share
+// token.
+type ReadingList struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Owner       uuid.UUID          `bson:"owner" validate:"required"`
+	Name        string             `bson:"name" validate:"required,max=100"`
+	Description *string            `bson:"description,omitempty" validate:"omitempty,max=500"`
+	Visibility  Visibility         `bson:"visibility" validate:"required,oneof=PRIVATE PUBLIC"`
+	ShareToken  *string            `bson:"shareToken,omitempty"`
+	Items       []Item             `bson:"items" validate:"max=500,dive"`
+	CreatedAt   time.Time          `bson:"createdAt" validate:"required"`
+	UpdatedAt   time.Time          `bson:"updatedAt" validate:"required"`
+}
+
+func NewReadingList(owner uuid.UUID, name string, description *string, visibility Visibility) (*ReadingList, error) {
+	now := time.Now()
+	l := ReadingList{
+		Owner:       owner,
+		Name:        name,
+		Description: description,
+		Visibility:  visibility,
+		Items:       []Item{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (list *ReadingList) Validate() error {
+	validate := validator.New()
+	return validate.Struct(list)
+}
+
+func (*ReadingList) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "updatedAt", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "shareToken", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	}
+
+	mongo.NewQueryBuilder[ReadingList](db, ReadingListCollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
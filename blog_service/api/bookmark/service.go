@@ -0,0 +1,340 @@
+package bookmark
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	"github.com/afteracademy/gomicro/blog-service/api/blog"
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	blogsDto "github.com/afteracademy/gomicro/blog-service/api/blogs/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/bookmark/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/bookmark/model"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	coredto "github.com/afteracademy/goserve/v2/dto"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Service keeps the bookmarks and the reading lists of the readers. Only
+// published blogs can be added, a blog that is unpublished or deleted later
+// is listed as not available.
+type Service interface {
+	GetPaginatedBookmarks(ctx context.Context, user *message.User, p *coredto.Pagination) ([]*dto.Item, error)
+	AddBookmark(ctx context.Context, blogId primitive.ObjectID, user *message.User) error
+	RemoveBookmark(ctx context.Context, blogId primitive.ObjectID, user *message.User) error
+	CreateReadingList(ctx context.Context, body *dto.CreateReadingList, user *message.User) (*dto.InfoReadingList, error)
+	GetPaginatedReadingLists(ctx context.Context, user *message.User, p *coredto.Pagination) ([]*dto.InfoReadingList, error)
+	GetReadingList(ctx context.Context, id primitive.ObjectID, user *message.User, p *coredto.Pagination) (*dto.ReadingList, error)
+	GetPublicReadingList(ctx context.Context, id primitive.ObjectID, p *coredto.Pagination) (*dto.ReadingList, error)
+	GetSharedReadingList(ctx context.Context, token string, p *coredto.Pagination) (*dto.ReadingList, error)
+	UpdateReadingList(ctx context.Context, id primitive.ObjectID, body *dto.UpdateReadingList, user *message.User) (*dto.InfoReadingList, error)
+	DeleteReadingList(ctx context.Context, id primitive.ObjectID, user *message.User) error
+	AddListBlog(ctx context.Context, id primitive.ObjectID, blogId primitive.ObjectID, user *message.User) (*dto.InfoReadingList, error)
+	RemoveListBlog(ctx context.Context, id primitive.ObjectID, blogId primitive.ObjectID, user *message.User) (*dto.InfoReadingList, error)
+	OrderReadingList(ctx context.Context, id primitive.ObjectID, body *dto.OrderReadingList, user *message.User) (*dto.InfoReadingList, error)
+	ShareReadingList(ctx context.Context, id primitive.ObjectID, user *message.User) (*dto.InfoReadingList, error)
+	UnshareReadingList(ctx context.Context, id primitive.ObjectID, user *message.User) (*dto.InfoReadingList, error)
+}
+
+type service struct {
+	bookmarkQueryBuilder    mongo.QueryBuilder[model.Bookmark]
+	readingListQueryBuilder mongo.QueryBuilder[model.ReadingList]
+	blogQueryBuilder        mongo.QueryBuilder[blogModel.Blog]
+	blogService             blog.Service
+}
+
+func NewService(db mongo.Database, blogService blog.Service) Service {
+	return &service{
+		bookmarkQueryBuilder:    mongo.NewQueryBuilder[model.Bookmark](db, model.BookmarkCollectionName),
+		readingListQueryBuilder: mongo.NewQueryBuilder[model.ReadingList](db, model.ReadingListCollectionName),
+		blogQueryBuilder:        mongo.NewQueryBuilder[blogModel.Blog](db, blogModel.CollectionName),
+		blogService:             blogService,
+	}
+}
+
+func (s *service) GetPaginatedBookmarks(ctx context.Context, user *message.User, p *coredto.Pagination) ([]*dto.Item, error) {
+	filter := bson.M{"user": user.ID}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+
+	bookmarks, err := s.bookmarkQueryBuilder.Query(ctx).FindPaginated(filter, p.Page, p.Limit, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.Item, len(bookmarks))
+	for i, b := range bookmarks {
+		items[i] = model.Item{BlogID: b.BlogID, AddedAt: b.CreatedAt}
+	}
+	return s.resolveItems(ctx, items)
+}
+
+func (s *service) AddBookmark(ctx context.Context, blogId primitive.ObjectID, user *message.User) error {
+	if err := s.blogService.CheckPublished(ctx, blogId); err != nil {
+		return err
+	}
+
+	bookmark, err := model.NewBookmark(user.ID, blogId)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.bookmarkQueryBuilder.Query(ctx).InsertOne(bookmark)
+	if err != nil && !mongod.IsDuplicateKeyError(err) {
+		return err
+	}
+	return nil
+}
+
+// RemoveBookmark does not check the blog, so that a bookmark of a blog that
+// is gone can still be removed.
+func (s *service) RemoveBookmark(ctx context.Context, blogId primitive.ObjectID, user *message.User) error {
+	filter := bson.M{"user": user.ID, "blogId": blogId}
+	_, err := s.bookmarkQueryBuilder.Query(ctx).DeleteOne(filter)
+	return err
+}
+
+func (s *service) CreateReadingList(ctx context.Context, body *dto.CreateReadingList, user *message.User) (*dto.InfoReadingList, error) {
+	list, err := model.NewReadingList(user.ID, body.Name, body.Description, body.Visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.readingListQueryBuilder.Query(ctx).InsertAndRetrieveOne(list)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewInfoReadingList(created, true), nil
+}
+
+func (s *service) GetPaginatedReadingLists(ctx context.Context, user *message.User, p *coredto.Pagination) ([]*dto.InfoReadingList, error) {
+	filter := bson.M{"owner": user.ID}
+	opts := options.Find().SetSort(bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: -1}})
+
+	lists, err := s.readingListQueryBuilder.Query(ctx).FindPaginated(filter, p.Page, p.Limit, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*dto.InfoReadingList, len(lists))
+	for i, l := range lists {
+		dtos[i] = dto.NewInfoReadingList(l, true)
+	}
+	return dtos, nil
+}
+
+func (s *service) GetReadingList(ctx context.Context, id primitive.ObjectID, user *message.User, p *coredto.Pagination) (*dto.ReadingList, error) {
+	return s.getReadingList(ctx, bson.M{"_id": id, "owner": user.ID}, p, true)
+}
+
+func (s *service) GetPublicReadingList(ctx context.Context, id primitive.ObjectID, p *coredto.Pagination) (*dto.ReadingList, error) {
+	return s.getReadingList(ctx, bson.M{"_id": id, "visibility": model.VisibilityPublic}, p, false)
+}
+
+func (s *service) GetSharedReadingList(ctx context.Context, token string, p *coredto.Pagination) (*dto.ReadingList, error) {
+	return s.getReadingList(ctx, bson.M{"shareToken": token}, p, false)
+}
+
+func (s *service) getReadingList(ctx context.Context, filter bson.M, p *coredto.Pagination, owner bool) (*dto.ReadingList, error) {
+	list, err := s.readingListQueryBuilder.Query(ctx).FindOne(filter, nil)
+	if err != nil {
+		return nil, network.NewNotFoundError("reading list not found", err)
+	}
+
+	start := min(int((p.Page-1)*p.Limit), len(list.Items))
+	end := min(start+int(p.Limit), len(list.Items))
+
+	items, err := s.resolveItems(ctx, list.Items[start:end])
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ReadingList{
+		InfoReadingList: *dto.NewInfoReadingList(list, owner),
+		Items:           items,
+	}, nil
+}
+
+func (s *service) UpdateReadingList(ctx context.Context, id primitive.ObjectID, body *dto.UpdateReadingList, user *message.User) (*dto.InfoReadingList, error) {
+	set := bson.M{"updatedAt": time.Now()}
+	if body.Name != nil {
+		set["name"] = *body.Name
+	}
+	if body.Description != nil {
+		set["description"] = *body.Description
+	}
+	if body.Visibility != nil {
+		set["visibility"] = *body.Visibility
+	}
+
+	return s.updateOwned(ctx, bson.M{"_id": id, "owner": user.ID}, bson.M{"$set": set})
+}
+
+func (s *service) DeleteReadingList(ctx context.Context, id primitive.ObjectID, user *message.User) error {
+	filter := bson.M{"_id": id, "owner": user.ID}
+	result, err := s.readingListQueryBuilder.Query(ctx).DeleteOne(filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return network.NewNotFoundError("reading list "+id.Hex()+" not found", nil)
+	}
+	return nil
+}
+
+// AddListBlog appends the blog to the end of the list, adding a blog that is
+// already in the list leaves the list as it is.
+func (s *service) AddListBlog(ctx context.Context, id primitive.ObjectID, blogId primitive.ObjectID, user *message.User) (*dto.InfoReadingList, error) {
+	if err := s.blogService.CheckPublished(ctx, blogId); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	filter := bson.M{
+		"_id":          id,
+		"owner":        user.ID,
+		"items.blogId": bson.M{"$ne": blogId},
+		"items." + strconv.Itoa(model.MaxListItems-1): bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$push": bson.M{"items": model.Item{BlogID: blogId, AddedAt: now}},
+		"$set":  bson.M{"updatedAt": now},
+	}
+
+	info, err := s.updateOwned(ctx, filter, update)
+	if !common.IsNotFound(err) {
+		return info, err
+	}
+
+	list, err := s.readingListQueryBuilder.Query(ctx).FindOne(bson.M{"_id": id, "owner": user.ID}, nil)
+	if err != nil {
+		return nil, network.NewNotFoundError("reading list "+id.Hex()+" not found", err)
+	}
+	for _, item := range list.Items {
+		if item.BlogID == blogId {
+			return dto.NewInfoReadingList(list, true), nil
+		}
+	}
+	return nil, network.NewBadRequestError("reading list can not have more than "+strconv.Itoa(model.MaxListItems)+" blogs", nil)
+}
+
+func (s *service) RemoveListBlog(ctx context.Context, id primitive.ObjectID, blogId primitive.ObjectID, user *message.User) (*dto.InfoReadingList, error) {
+	filter := bson.M{"_id": id, "owner": user.ID}
+	update := bson.M{
+		"$pull": bson.M{"items": bson.M{"blogId": blogId}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	return s.updateOwned(ctx, filter, update)
+}
+
+// OrderReadingList replaces the order of the list. It is refused when the
+// list was changed since it was read, so that a blog added meanwhile is not
+// dropped.
+func (s *service) OrderReadingList(ctx context.Context, id primitive.ObjectID, body *dto.OrderReadingList, user *message.User) (*dto.InfoReadingList, error) {
+	list, err := s.readingListQueryBuilder.Query(ctx).FindOne(bson.M{"_id": id, "owner": user.ID}, nil)
+	if err != nil {
+		return nil, network.NewNotFoundError("reading list "+id.Hex()+" not found", err)
+	}
+
+	current := make(map[primitive.ObjectID]model.Item, len(list.Items))
+	for _, item := range list.Items {
+		current[item.BlogID] = item
+	}
+
+	items := make([]model.Item, 0, len(body.BlogIDs))
+	for _, blogId := range body.BlogIDs {
+		item, ok := current[blogId]
+		if !ok {
+			break
+		}
+		delete(current, blogId)
+		items = append(items, item)
+	}
+	if len(items) != len(list.Items) || len(current) != 0 {
+		return nil, network.NewBadRequestError("blogIds must list every blog of the reading list once", nil)
+	}
+
+	filter := bson.M{"_id": id, "owner": user.ID, "updatedAt": list.UpdatedAt}
+	update := bson.M{"$set": bson.M{"items": items, "updatedAt": time.Now()}}
+	info, err := s.updateOwned(ctx, filter, update)
+	if common.IsNotFound(err) {
+		return nil, common.NewConflictError("reading list was changed meanwhile, reload it and try again", nil)
+	}
+	return info, err
+}
+
+// ShareReadingList gives the list a new share token, the previous token no
+// longer works.
+func (s *service) ShareReadingList(ctx context.Context, id primitive.ObjectID, user *message.User) (*dto.InfoReadingList, error) {
+	filter := bson.M{"_id": id, "owner": user.ID}
+	update := bson.M{"$set": bson.M{"shareToken": uuid.NewString(), "updatedAt": time.Now()}}
+	return s.updateOwned(ctx, filter, update)
+}
+
+func (s *service) UnshareReadingList(ctx context.Context, id primitive.ObjectID, user *message.User) (*dto.InfoReadingList, error) {
+	filter := bson.M{"_id": id, "owner": user.ID}
+	update := bson.M{"$unset": bson.M{"shareToken": ""}, "$set": bson.M{"updatedAt": time.Now()}}
+	return s.updateOwned(ctx, filter, update)
+}
+
+func (s *service) updateOwned(ctx context.Context, filter bson.M, update bson.M) (*dto.InfoReadingList, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var list model.ReadingList
+	err := s.readingListQueryBuilder.GetCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&list)
+	if err == mongod.ErrNoDocuments {
+		return nil, network.NewNotFoundError("reading list not found", nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewInfoReadingList(&list, true), nil
+}
+
+// resolveItems fills in the details of the blogs that are still published.
+func (s *service) resolveItems(ctx context.Context, items []model.Item) ([]*dto.Item, error) {
+	dtos := make([]*dto.Item, len(items))
+	if len(items) == 0 {
+		return dtos, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(items))
+	for i, item := range items {
+		ids[i] = item.BlogID
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": ids}, "state": blogModel.StatePublished}
+	opts := options.Find().SetProjection(bson.D{{Key: "draftText", Value: 0}, {Key: "text", Value: 0}})
+	blogs, err := s.blogQueryBuilder.Query(ctx).FindAll(filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	published := make(map[primitive.ObjectID]*blogsDto.ItemBlog, len(blogs))
+	for _, b := range blogs {
+		d, err := blogsDto.NewItemBlog(b)
+		if err != nil {
+			return nil, err
+		}
+		published[b.ID] = d
+	}
+
+	for i, item := range items {
+		blog, ok := published[item.BlogID]
+		dtos[i] = &dto.Item{
+			BlogID:    item.BlogID,
+			AddedAt:   item.AddedAt,
+			Available: ok,
+			Blog:      blog,
+		}
+	}
+	return dtos, nil
+}
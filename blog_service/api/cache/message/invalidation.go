@@ -0,0 +1,15 @@
+package message
+
+import (
+	blogv1 "github.com/afteracademy/gomicro/contracts/blog/v1"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BlogCacheInvalidation = blogv1.BlogCacheInvalidation
+
+func NewBlogCacheInvalidation(blogId primitive.ObjectID, slugs ...string) *BlogCacheInvalidation {
+	return &BlogCacheInvalidation{
+		BlogID: blogId.Hex(),
+		Slugs:  slugs,
+	}
+}
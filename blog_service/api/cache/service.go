@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/blog"
+	"github.com/afteracademy/gomicro/blog-service/api/blogs"
+	"github.com/afteracademy/gomicro/blog-service/api/cache/message"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	blogv1 "github.com/afteracademy/gomicro/contracts/blog/v1"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/nats-io/nats.go"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const NATS_TOPIC_INVALIDATE = blogv1.NATS_BROADCAST_BLOG_CACHE_INVALIDATE
+
+// Service drops the cached copies of a blog whenever its public state changes.
+// The caches of this instance are dropped right away, the other instances are
+// told over NATS with a plain subscription, so that each of them gets it. The
+// feeds and the sitemaps, which all the instances share, are refreshed by the
+// paths that change the publication instead.
+type Service interface {
+	InvalidateBlog(ctx context.Context, blogId primitive.ObjectID, slugs ...string) error
+	Subscribe() error
+	Unsubscribe()
+}
+
+type service struct {
+	natsClient   micro.NatsClient
+	identity     common.ServiceIdentity
+	blogService  blog.Service
+	blogsService blogs.Service
+	logger       *slog.Logger
+	timeout      time.Duration
+	subscription *nats.Subscription
+}
+
+func NewService(natsClient micro.NatsClient, identity common.ServiceIdentity, blogService blog.Service, blogsService blogs.Service, logger *slog.Logger) Service {
+	return &service{
+		natsClient:   natsClient,
+		identity:     identity,
+		blogService:  blogService,
+		blogsService: blogsService,
+		logger:       logger,
+		timeout:      natsClient.GetInstance().Timeout,
+	}
+}
+
+func (s *service) InvalidateBlog(ctx context.Context, blogId primitive.ObjectID, slugs ...string) error {
+	if err := s.drop(ctx, blogId, slugs); err != nil {
+		return err
+	}
+
+	// the other instances are best effort, their copies expire anyway
+	err := s.broadcast(message.NewBlogCacheInvalidation(blogId, slugs...))
+	common.FollowUp(s.logger, "cache: invalidation broadcast", blogId, err)
+	return nil
+}
+
+func (s *service) Subscribe() error {
+	sub, err := s.natsClient.GetInstance().Conn.Subscribe(NATS_TOPIC_INVALIDATE, s.onInvalidation)
+	if err != nil {
+		return err
+	}
+	s.subscription = sub
+	return nil
+}
+
+func (s *service) Unsubscribe() {
+	if s.subscription != nil {
+		s.subscription.Unsubscribe()
+	}
+}
+
+func (s *service) onInvalidation(msg *nats.Msg) {
+	// anyone on the bus could otherwise flush the caches of every instance
+	if err := s.identity.Verify(msg); err != nil {
+		s.logger.Warn("cache: invalidation rejected", "error", err)
+		return
+	}
+
+	invalidation, err := micro.JsonToMsg[message.BlogCacheInvalidation](msg.Data)
+	if err != nil {
+		return
+	}
+
+	blogId, err := primitive.ObjectIDFromHex(invalidation.BlogID)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	err = s.drop(ctx, blogId, invalidation.Slugs)
+	common.FollowUp(s.logger, "cache: invalidation", blogId, err)
+}
+
+func (s *service) drop(ctx context.Context, blogId primitive.ObjectID, slugs []string) error {
+	if err := s.blogService.DeleteBlogDtoCache(ctx, blogId, slugs...); err != nil {
+		return err
+	}
+	return s.blogsService.DeleteSimilarBlogsDtoCache(ctx, blogId)
+}
+
+func (s *service) broadcast(invalidation *message.BlogCacheInvalidation) error {
+	data, err := micro.MsgToJson(invalidation)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(NATS_TOPIC_INVALIDATE)
+	msg.Data = data
+	s.identity.Sign(msg)
+	return s.natsClient.GetInstance().Conn.PublishMsg(msg)
+}
@@ -0,0 +1,163 @@
+package comment
+
+import (
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	"github.com/afteracademy/gomicro/blog-service/api/comment/dto"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	coredto "github.com/afteracademy/goserve/v2/dto"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+)
+
+type controller struct {
+	micro.Controller
+	common.ContextPayload
+	authorizeMFunc network.AuthorizationProvider
+	service        Service
+}
+
+func NewController(
+	authMFunc network.AuthenticationProvider,
+	authorizeMFunc network.AuthorizationProvider,
+	service Service,
+) micro.Controller {
+	return &controller{
+		Controller:     micro.NewController("/comment", authMFunc, authorizeMFunc),
+		ContextPayload: common.NewContextPayload(),
+		authorizeMFunc: authorizeMFunc,
+		service:        service,
+	}
+}
+
+func (c *controller) MountNats(group micro.NatsGroup) {}
+
+func (c *controller) MountRoutes(group *gin.RouterGroup) {
+	group.GET("/blog/id/:id", c.getCommentsHandler)
+
+	private := group.Group("", c.Authentication())
+	private.POST("/blog/id/:id", c.postCommentHandler)
+	private.PUT("/id/:id", c.updateCommentHandler)
+	private.DELETE("/id/:id", c.deleteCommentHandler)
+
+	// Authorization takes a single role, moderation is open to either
+	moderation := private.Group("", c.authorizeMFunc.Middleware(string(message.RoleCodeEditor), string(message.RoleCodeAdmin)))
+	moderation.PUT("/moderate/id/:id", c.moderateCommentHandler)
+}
+
+func (c *controller) getCommentsHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	comments, err := c.service.GetPaginatedThreads(ctx.Request.Context(), mongoId.ID, pagination)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", &comments)
+}
+
+func (c *controller) postCommentHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	body, err := network.ReqBody[dto.CreateComment](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	if body.ParentId != nil && body.ParentID == nil {
+		network.SendBadRequestError(ctx, "parentId is not a valid id", nil)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	comment, err := c.service.CreateComment(ctx.Request.Context(), mongoId.ID, body, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "comment created successfully", comment)
+}
+
+func (c *controller) updateCommentHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	body, err := network.ReqBody[dto.UpdateComment](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	comment, err := c.service.UpdateComment(ctx.Request.Context(), mongoId.ID, body, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "comment updated successfully", comment)
+}
+
+func (c *controller) deleteCommentHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	err = c.service.DeleteComment(ctx.Request.Context(), mongoId.ID, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessMsgResponse(ctx, "comment deleted successfully")
+}
+
+func (c *controller) moderateCommentHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	body, err := network.ReqBody[dto.ModerateComment](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	comment, err := c.service.ModerateComment(ctx.Request.Context(), mongoId.ID, body, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "comment moderated successfully", comment)
+}
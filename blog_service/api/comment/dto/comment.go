@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	authorDto "github.com/afteracademy/gomicro/blog-service/api/author/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/comment/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Comment struct {
+	ID        primitive.ObjectID    `json:"_id"`
+	ParentID  *primitive.ObjectID   `json:"parentId,omitempty"`
+	Text      *string               `json:"text,omitempty"`
+	Status    model.Status          `json:"status"`
+	Author    *authorDto.InfoAuthor `json:"author,omitempty"`
+	EditedAt  *time.Time            `json:"editedAt,omitempty"`
+	CreatedAt time.Time             `json:"createdAt"`
+	Replies   []*Comment            `json:"replies"`
+}
+
+// NewComment leaves out the text of a comment that is no longer shown, and the
+// author when it could not be found.
+func NewComment(comment *model.Comment, author *message.User) *Comment {
+	c := &Comment{
+		ID:        comment.ID,
+		ParentID:  comment.ParentID,
+		Status:    comment.Status,
+		EditedAt:  comment.EditedAt,
+		CreatedAt: comment.CreatedAt,
+		Replies:   []*Comment{},
+	}
+	if comment.Status == model.StatusActive {
+		c.Text = &comment.Text
+	}
+	if author != nil {
+		c.Author = authorDto.NewInfoPrivateUser(author)
+	}
+	return c
+}
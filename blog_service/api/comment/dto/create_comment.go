@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreateComment struct {
+	Text     string              `json:"text" validate:"required,min=1,max=5000"`
+	ParentId *string             `json:"parentId,omitempty" validate:"omitempty,len=24,hexadecimal"`
+	ParentID *primitive.ObjectID `json:"-" validate:"-"`
+}
+
+func EmptyCreateComment() *CreateComment {
+	return &CreateComment{}
+}
+
+func (d *CreateComment) GetValue() *CreateComment {
+	if d.ParentId != nil {
+		id, err := mongo.NewObjectID(*d.ParentId)
+		if err == nil {
+			d.ParentID = &id
+		}
+	}
+	return d
+}
+
+func (d *CreateComment) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+// ModerateComment hides a comment from the readers, or shows it again.
+type ModerateComment struct {
+	Hidden bool    `json:"hidden"`
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
+func EmptyModerateComment() *ModerateComment {
+	return &ModerateComment{}
+}
+
+func (d *ModerateComment) GetValue() *ModerateComment {
+	return d
+}
+
+func (d *ModerateComment) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
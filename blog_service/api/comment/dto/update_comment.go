@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+type UpdateComment struct {
+	Text string `json:"text" validate:"required,min=1,max=5000"`
+}
+
+func EmptyUpdateComment() *UpdateComment {
+	return &UpdateComment{}
+}
+
+func (d *UpdateComment) GetValue() *UpdateComment {
+	return d
+}
+
+func (d *UpdateComment) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "comments"
+
+type Status string
+
+const (
+	StatusActive  Status = "ACTIVE"
+	StatusDeleted Status = "DELETED"
+	StatusHidden  Status = "HIDDEN"
+)
+
+// Comment is a reader comment on a published blog. A reply points at its
+// parent, and every comment of a thread at the top level comment, its root,
+// so that a thread is read with one query. Deleted and hidden comments stay
+// in place to keep their replies attached.
+type Comment struct {
+	ID               primitive.ObjectID  `bson:"_id" validate:"required"`
+	BlogID           primitive.ObjectID  `bson:"blogId" validate:"required"`
+	ParentID         *primitive.ObjectID `bson:"parentId"`
+	RootID           primitive.ObjectID  `bson:"rootId" validate:"required"`
+	Text             string              `bson:"text" validate:"required,max=5000"`
+	Author           uuid.UUID           `bson:"author" validate:"required"`
+	Status           Status              `bson:"status" validate:"required"`
+	EditedAt         *time.Time          `bson:"editedAt,omitempty"`
+	ModeratedBy      *uuid.UUID          `bson:"moderatedBy,omitempty"`
+	ModerationReason *string             `bson:"moderationReason,omitempty"`
+	CreatedAt        time.Time           `bson:"createdAt" validate:"required"`
+	UpdatedAt        time.Time           `bson:"updatedAt" validate:"required"`
+}
+
+func NewComment(blogId primitive.ObjectID, parent *Comment, text string, author uuid.UUID) (*Comment, error) {
+	now := time.Now()
+	c := Comment{
+		ID:        primitive.NewObjectID(),
+		BlogID:    blogId,
+		Text:      text,
+		Author:    author,
+		Status:    StatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	c.RootID = c.ID
+	if parent != nil {
+		c.ParentID = &parent.ID
+		c.RootID = parent.RootID
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (comment *Comment) Validate() error {
+	validate := validator.New()
+	return validate.Struct(comment)
+}
+
+func (*Comment) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{Keys: bson.D{{Key: "blogId", Value: 1}, {Key: "parentId", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "rootId", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "author", Value: 1}}},
+	}
+
+	mongo.NewQueryBuilder[Comment](db, CollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
@@ -0,0 +1,246 @@
+package comment
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth"
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	"github.com/afteracademy/gomicro/blog-service/api/blog"
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/gomicro/blog-service/api/comment/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/comment/model"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	coredto "github.com/afteracademy/goserve/v2/dto"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Service interface {
+	GetPaginatedThreads(ctx context.Context, blogId primitive.ObjectID, p *coredto.Pagination) ([]*dto.Comment, error)
+	CreateComment(ctx context.Context, blogId primitive.ObjectID, body *dto.CreateComment, user *message.User) (*dto.Comment, error)
+	UpdateComment(ctx context.Context, id primitive.ObjectID, body *dto.UpdateComment, user *message.User) (*dto.Comment, error)
+	DeleteComment(ctx context.Context, id primitive.ObjectID, user *message.User) error
+	ModerateComment(ctx context.Context, id primitive.ObjectID, body *dto.ModerateComment, moderator *message.User) (*dto.Comment, error)
+}
+
+type service struct {
+	commentQueryBuilder mongo.QueryBuilder[model.Comment]
+	blogQueryBuilder    mongo.QueryBuilder[blogModel.Blog]
+	authService         auth.Service
+	blogService         blog.Service
+	logger              *slog.Logger
+}
+
+func NewService(db mongo.Database, authService auth.Service, blogService blog.Service, logger *slog.Logger) Service {
+	return &service{
+		commentQueryBuilder: mongo.NewQueryBuilder[model.Comment](db, model.CollectionName),
+		blogQueryBuilder:    mongo.NewQueryBuilder[blogModel.Blog](db, blogModel.CollectionName),
+		authService:         authService,
+		blogService:         blogService,
+		logger:              logger,
+	}
+}
+
+// GetPaginatedThreads pages through the top level comments of a published
+// blog, oldest first, each with all of its replies. The authors of the page
+// are looked up in one batch.
+func (s *service) GetPaginatedThreads(ctx context.Context, blogId primitive.ObjectID, p *coredto.Pagination) ([]*dto.Comment, error) {
+	if err := s.blogService.CheckPublished(ctx, blogId); err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"blogId": blogId, "parentId": nil}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	roots, err := s.commentQueryBuilder.Query(ctx).FindPaginated(filter, p.Page, p.Limit, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) == 0 {
+		return []*dto.Comment{}, nil
+	}
+
+	rootIds := make([]primitive.ObjectID, len(roots))
+	for i, r := range roots {
+		rootIds[i] = r.ID
+	}
+
+	filter = bson.M{"rootId": bson.M{"$in": rootIds}, "parentId": bson.M{"$ne": nil}}
+	replies, err := s.commentQueryBuilder.Query(ctx).FindAll(filter, opts)
+	if err != nil && err != mongod.ErrNoDocuments {
+		return nil, err
+	}
+
+	comments := append(roots, replies...)
+	authors := s.findAuthors(ctx, comments)
+
+	nodes := make(map[primitive.ObjectID]*dto.Comment, len(comments))
+	for _, c := range comments {
+		nodes[c.ID] = dto.NewComment(c, authors[c.Author])
+	}
+
+	threads := make([]*dto.Comment, len(roots))
+	for i, r := range roots {
+		threads[i] = nodes[r.ID]
+	}
+	for _, r := range replies {
+		parent, ok := nodes[*r.ParentID]
+		if !ok {
+			parent = nodes[r.RootID]
+		}
+		parent.Replies = append(parent.Replies, nodes[r.ID])
+	}
+
+	return threads, nil
+}
+
+// findAuthors is best effort, the comments are still listed without their
+// authors while auth_service is not reachable.
+func (s *service) findAuthors(ctx context.Context, comments []*model.Comment) map[uuid.UUID]*message.User {
+	ids := make([]uuid.UUID, len(comments))
+	for i, c := range comments {
+		ids[i] = c.Author
+	}
+
+	authors, err := s.authService.FindUserPublicProfiles(ctx, ids)
+	if err != nil {
+		s.logger.Warn("comment: author lookup failed", "error", err)
+		return map[uuid.UUID]*message.User{}
+	}
+	return authors
+}
+
+func (s *service) CreateComment(ctx context.Context, blogId primitive.ObjectID, body *dto.CreateComment, user *message.User) (*dto.Comment, error) {
+	if err := s.blogService.CheckPublished(ctx, blogId); err != nil {
+		return nil, err
+	}
+
+	var parent *model.Comment
+	if body.ParentID != nil {
+		filter := bson.M{"_id": *body.ParentID, "blogId": blogId, "status": model.StatusActive}
+		p, err := s.commentQueryBuilder.Query(ctx).FindOne(filter, nil)
+		if err != nil {
+			return nil, network.NewNotFoundError("comment "+body.ParentID.Hex()+" not found for the blog", err)
+		}
+		parent = p
+	}
+
+	comment, err := model.NewComment(blogId, parent, body.Text, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.commentQueryBuilder.Query(ctx).InsertAndRetrieveOne(comment)
+	if err != nil {
+		return nil, err
+	}
+
+	s.countComments(ctx, blogId, 1)
+
+	return dto.NewComment(created, user), nil
+}
+
+func (s *service) UpdateComment(ctx context.Context, id primitive.ObjectID, body *dto.UpdateComment, user *message.User) (*dto.Comment, error) {
+	if _, err := s.findOwned(ctx, id, user); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	filter := bson.M{"_id": id, "author": user.ID, "status": model.StatusActive}
+	update := bson.M{"$set": bson.M{"text": body.Text, "editedAt": now, "updatedAt": now}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var comment model.Comment
+	err := s.commentQueryBuilder.GetCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&comment)
+	if err == mongod.ErrNoDocuments {
+		return nil, network.NewNotFoundError("comment "+id.Hex()+" not found", nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewComment(&comment, user), nil
+}
+
+// DeleteComment keeps the comment without its text so that the replies stay
+// in the thread.
+func (s *service) DeleteComment(ctx context.Context, id primitive.ObjectID, user *message.User) error {
+	if _, err := s.findOwned(ctx, id, user); err != nil {
+		return err
+	}
+
+	_, err := s.setStatus(ctx, id, model.StatusActive, model.StatusDeleted, nil)
+	return err
+}
+
+func (s *service) ModerateComment(ctx context.Context, id primitive.ObjectID, body *dto.ModerateComment, moderator *message.User) (*dto.Comment, error) {
+	from, to := model.StatusHidden, model.StatusActive
+	if body.Hidden {
+		from, to = model.StatusActive, model.StatusHidden
+	}
+
+	set := bson.M{"moderatedBy": moderator.ID, "moderationReason": body.Reason}
+	comment, err := s.setStatus(ctx, id, from, to, set)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewComment(comment, nil), nil
+}
+
+// setStatus moves the comment from one status to another and keeps the
+// comment count of the blog in step, only active comments are counted.
+func (s *service) setStatus(ctx context.Context, id primitive.ObjectID, from, to model.Status, set bson.M) (*model.Comment, error) {
+	if set == nil {
+		set = bson.M{}
+	}
+	set["status"] = to
+	set["updatedAt"] = time.Now()
+
+	filter := bson.M{"_id": id, "status": from}
+	update := bson.M{"$set": set}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var comment model.Comment
+	err := s.commentQueryBuilder.GetCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&comment)
+	if err == mongod.ErrNoDocuments {
+		return nil, network.NewNotFoundError("comment "+id.Hex()+" is not "+string(from), nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if from == model.StatusActive {
+		s.countComments(ctx, comment.BlogID, -1)
+	} else if to == model.StatusActive {
+		s.countComments(ctx, comment.BlogID, 1)
+	}
+
+	return &comment, nil
+}
+
+func (s *service) findOwned(ctx context.Context, id primitive.ObjectID, user *message.User) (*model.Comment, error) {
+	filter := bson.M{"_id": id, "status": model.StatusActive}
+	comment, err := s.commentQueryBuilder.Query(ctx).FindOne(filter, nil)
+	if err != nil {
+		return nil, network.NewNotFoundError("comment "+id.Hex()+" not found", err)
+	}
+	if comment.Author != user.ID {
+		return nil, network.NewForbiddenError("permission denied", nil)
+	}
+	return comment, nil
+}
+
+func (s *service) countComments(ctx context.Context, blogId primitive.ObjectID, delta int64) {
+	filter := bson.M{"_id": blogId}
+	update := bson.M{"$inc": bson.M{"commentCount": delta}}
+	_, err := s.blogQueryBuilder.GetCollection().UpdateOne(context.WithoutCancel(ctx), filter, update)
+	common.FollowUp(s.logger, "comment: count update", blogId, err)
+}
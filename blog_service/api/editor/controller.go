@@ -2,6 +2,10 @@ package editor
 
 import (
 	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	blogDto "github.com/afteracademy/gomicro/blog-service/api/blog/dto"
+	reviewDto "github.com/afteracademy/gomicro/blog-service/api/review/dto"
+	revisionDto "github.com/afteracademy/gomicro/blog-service/api/revision/dto"
+	scheduleDto "github.com/afteracademy/gomicro/blog-service/api/schedule/dto"
 	"github.com/afteracademy/gomicro/blog-service/common"
 	coredto "github.com/afteracademy/goserve/v2/dto"
 	"github.com/afteracademy/goserve/v2/micro"
@@ -34,8 +38,16 @@ func (c *controller) MountRoutes(group *gin.RouterGroup) {
 	group.GET("/id/:id", c.getBlogHandler)
 	group.PUT("/publish/id/:id", c.publishBlogHandler)
 	group.PUT("/unpublish/id/:id", c.unpublishBlogHandler)
+	group.PUT("/schedule/id/:id", c.scheduleBlogHandler)
+	group.GET("/scheduled", c.getScheduledHandler)
+	group.DELETE("/scheduled/id/:id", c.cancelScheduleHandler)
+	group.PUT("/reject/id/:id", c.rejectBlogHandler)
+	group.POST("/review/id/:id", c.postReviewHandler)
 	group.GET("/submitted", c.getSubmittedBlogsHandler)
 	group.GET("/published", c.getPublishedBlogsHandler)
+	group.GET("/revisions/id/:id", c.getRevisionsHandler)
+	group.GET("/revisions/diff/id/:id", c.getRevisionsDiffHandler)
+	group.GET("/transitions/id/:id", c.getTransitionsHandler)
 }
 
 func (c *controller) getBlogHandler(ctx *gin.Context) {
@@ -45,12 +57,13 @@ func (c *controller) getBlogHandler(ctx *gin.Context) {
 		return
 	}
 
-	blog, err := c.service.GetBlogById(mongoId.ID)
+	blog, err := c.service.GetBlogById(ctx.Request.Context(), mongoId.ID)
 	if err != nil {
 		network.SendNotFoundError(ctx, mongoId.ID.Hex()+" not found", err)
 		return
 	}
 
+	common.SetVersion(ctx, blog.Version)
 	network.SendSuccessDataResponse(ctx, "success", blog)
 }
 
@@ -61,11 +74,17 @@ func (c *controller) publishBlogHandler(ctx *gin.Context) {
 		return
 	}
 
+	version, err := c.reqVersion(ctx)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
 	user := c.MustGetUser(ctx)
 
-	err = c.service.BlogPublication(mongoId.ID, user, true)
+	err = c.service.BlogPublication(ctx.Request.Context(), mongoId.ID, user, true, version)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
@@ -79,45 +98,285 @@ func (c *controller) unpublishBlogHandler(ctx *gin.Context) {
 		return
 	}
 
+	version, err := c.reqVersion(ctx)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
 	user := c.MustGetUser(ctx)
 
-	err = c.service.BlogPublication(mongoId.ID, user, false)
+	err = c.service.BlogPublication(ctx.Request.Context(), mongoId.ID, user, false, version)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
 	network.SendSuccessMsgResponse(ctx, "blog unpublished successfully")
 }
 
-func (c *controller) getSubmittedBlogsHandler(ctx *gin.Context) {
+// reqVersion takes the version from If-Match, or else from the optional body
+// of an action.
+func (c *controller) reqVersion(ctx *gin.Context) (int64, error) {
+	var version *int64
+	if ctx.GetHeader("If-Match") == "" && ctx.Request.ContentLength != 0 {
+		body, err := network.ReqBody[blogDto.BlogVersion](ctx)
+		if err != nil {
+			return 0, network.NewBadRequestError(err.Error(), err)
+		}
+		version = body.Version
+	}
+	return common.ReqVersion(ctx, version)
+}
+
+func (c *controller) scheduleBlogHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	body, err := network.ReqBody[scheduleDto.ScheduleBlog](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	version, err := common.ReqVersion(ctx, body.Version)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+	body.Version = &version
+
+	user := c.MustGetUser(ctx)
+
+	schedules, err := c.service.ScheduleBlog(ctx.Request.Context(), mongoId.ID, body, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "blog scheduled successfully", &schedules)
+}
+
+func (c *controller) getScheduledHandler(ctx *gin.Context) {
 	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
 	if err != nil {
 		network.SendBadRequestError(ctx, err.Error(), err)
 		return
 	}
 
-	blogs, err := c.service.GetPaginatedSubmitted(pagination)
+	schedules, err := c.service.GetPaginatedScheduled(ctx.Request.Context(), pagination)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", &schedules)
+}
+
+func (c *controller) cancelScheduleHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	err = c.service.CancelSchedule(ctx.Request.Context(), mongoId.ID, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessMsgResponse(ctx, "schedule cancelled successfully")
+}
+
+func (c *controller) rejectBlogHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	body, err := network.ReqBody[reviewDto.RejectBlog](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	review, err := c.service.RejectBlog(ctx.Request.Context(), mongoId.ID, user, body.Reason)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "blog rejected successfully", review)
+}
+
+func (c *controller) postReviewHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	body, err := network.ReqBody[reviewDto.CreateReview](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	if body.ParentId != nil && body.ParentID == nil {
+		network.SendBadRequestError(ctx, "parentId is not a valid id", nil)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	review, err := c.service.AddReview(ctx.Request.Context(), mongoId.ID, body, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "review added successfully", review)
+}
+
+func (c *controller) getSubmittedBlogsHandler(ctx *gin.Context) {
+	if common.HasCursor(ctx) {
+		c.getSubmittedBlogsByCursorHandler(ctx)
+		return
+	}
+
+	query, err := network.ReqQuery[blogDto.ListQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	blogs, err := c.service.GetPaginatedSubmitted(ctx.Request.Context(), query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", blogs)
+}
+
+func (c *controller) getSubmittedBlogsByCursorHandler(ctx *gin.Context) {
+	query, err := network.ReqQuery[blogDto.CursorQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	page, err := c.service.GetSubmittedByCursor(ctx.Request.Context(), query)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
-	network.SendSuccessDataResponse(ctx, "success", &blogs)
+	network.SendSuccessDataResponse(ctx, "success", page)
 }
 
 func (c *controller) getPublishedBlogsHandler(ctx *gin.Context) {
+	if common.HasCursor(ctx) {
+		c.getPublishedBlogsByCursorHandler(ctx)
+		return
+	}
+
+	query, err := network.ReqQuery[blogDto.ListQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	blogs, err := c.service.GetPaginatedPublished(ctx.Request.Context(), query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", blogs)
+}
+
+func (c *controller) getPublishedBlogsByCursorHandler(ctx *gin.Context) {
+	query, err := network.ReqQuery[blogDto.CursorQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	page, err := c.service.GetPublishedByCursor(ctx.Request.Context(), query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", page)
+}
+
+func (c *controller) getRevisionsHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
 	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
 	if err != nil {
 		network.SendBadRequestError(ctx, err.Error(), err)
 		return
 	}
 
-	blogs, err := c.service.GetPaginatedPublished(pagination)
+	revisions, err := c.service.GetRevisions(ctx.Request.Context(), mongoId.ID, pagination)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", &revisions)
+}
+
+func (c *controller) getRevisionsDiffHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	query, err := network.ReqQuery[revisionDto.DiffQuery](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	diff, err := c.service.DiffRevisions(ctx.Request.Context(), mongoId.ID, query)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", diff)
+}
+
+func (c *controller) getTransitionsHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	transitions, err := c.service.GetTransitions(ctx.Request.Context(), mongoId.ID)
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
-	network.SendSuccessDataResponse(ctx, "success", &blogs)
+	network.SendSuccessDataResponse(ctx, "success", &transitions)
 }
@@ -1,13 +1,36 @@
 package editor
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"time"
 
 	"github.com/afteracademy/gomicro/blog-service/api/auth"
 	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
 	authorDto "github.com/afteracademy/gomicro/blog-service/api/author/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/autocomplete"
 	"github.com/afteracademy/gomicro/blog-service/api/blog/dto"
 	"github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/gomicro/blog-service/api/blogs"
+	"github.com/afteracademy/gomicro/blog-service/api/cache"
+	"github.com/afteracademy/gomicro/blog-service/api/event"
+	eventMsg "github.com/afteracademy/gomicro/blog-service/api/event/message"
+	"github.com/afteracademy/gomicro/blog-service/api/review"
+	reviewDto "github.com/afteracademy/gomicro/blog-service/api/review/dto"
+	reviewModel "github.com/afteracademy/gomicro/blog-service/api/review/model"
+	"github.com/afteracademy/gomicro/blog-service/api/revision"
+	revisionDto "github.com/afteracademy/gomicro/blog-service/api/revision/dto"
+	revisionModel "github.com/afteracademy/gomicro/blog-service/api/revision/model"
+	"github.com/afteracademy/gomicro/blog-service/api/schedule"
+	scheduleDto "github.com/afteracademy/gomicro/blog-service/api/schedule/dto"
+	scheduleModel "github.com/afteracademy/gomicro/blog-service/api/schedule/model"
+	"github.com/afteracademy/gomicro/blog-service/api/tag"
+	"github.com/afteracademy/gomicro/blog-service/api/workflow"
+	workflowDto "github.com/afteracademy/gomicro/blog-service/api/workflow/dto"
+	workflowModel "github.com/afteracademy/gomicro/blog-service/api/workflow/model"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	blogv1 "github.com/afteracademy/gomicro/contracts/blog/v1"
 	coredto "github.com/afteracademy/goserve/v2/dto"
 	"github.com/afteracademy/goserve/v2/mongo"
 	"github.com/afteracademy/goserve/v2/network"
@@ -17,99 +40,382 @@ import (
 )
 
 type Service interface {
-	GetBlogById(id primitive.ObjectID) (*authorDto.PrivateBlog, error)
-	BlogPublication(blogId primitive.ObjectID, editor *message.User, publish bool) error
-	GetPaginatedPublished(p *coredto.Pagination) ([]*dto.InfoBlog, error)
-	GetPaginatedSubmitted(p *coredto.Pagination) ([]*dto.InfoBlog, error)
+	GetBlogById(ctx context.Context, id primitive.ObjectID) (*authorDto.PrivateBlog, error)
+	BlogPublication(ctx context.Context, blogId primitive.ObjectID, editor *message.User, publish bool, version int64) error
+	ScheduleBlog(ctx context.Context, blogId primitive.ObjectID, body *scheduleDto.ScheduleBlog, editor *message.User) ([]*scheduleDto.InfoSchedule, error)
+	GetPaginatedScheduled(ctx context.Context, p *coredto.Pagination) ([]*scheduleDto.InfoSchedule, error)
+	CancelSchedule(ctx context.Context, scheduleId primitive.ObjectID, editor *message.User) error
+	RunSchedule(ctx context.Context, schedule *scheduleModel.Schedule) error
+	RefreshBlog(ctx context.Context, blog *model.Blog, e *eventMsg.BlogEvent) error
+	RejectBlog(ctx context.Context, blogId primitive.ObjectID, editor *message.User, reason string) (*reviewDto.Review, error)
+	AddReview(ctx context.Context, blogId primitive.ObjectID, body *reviewDto.CreateReview, editor *message.User) (*reviewDto.Review, error)
+	GetPaginatedPublished(ctx context.Context, query *dto.ListQuery) (*dto.Page[*dto.InfoBlog], error)
+	GetPaginatedSubmitted(ctx context.Context, query *dto.ListQuery) (*dto.Page[*dto.InfoBlog], error)
+	GetPublishedByCursor(ctx context.Context, query *dto.CursorQuery) (*dto.CursorPage[*dto.InfoBlog], error)
+	GetSubmittedByCursor(ctx context.Context, query *dto.CursorQuery) (*dto.CursorPage[*dto.InfoBlog], error)
+	GetRevisions(ctx context.Context, blogId primitive.ObjectID, p *coredto.Pagination) ([]*revisionDto.InfoRevision, error)
+	DiffRevisions(ctx context.Context, blogId primitive.ObjectID, query *revisionDto.DiffQuery) (*revisionDto.RevisionDiff, error)
+	GetTransitions(ctx context.Context, blogId primitive.ObjectID) ([]*workflowDto.Transition, error)
 }
 
 type service struct {
-	blogQueryBuilder mongo.QueryBuilder[model.Blog]
-	authService      auth.Service
+	blogQueryBuilder    mongo.QueryBuilder[model.Blog]
+	authService         auth.Service
+	eventService        event.Service
+	cacheService        cache.Service
+	autocompleteService autocomplete.Service
+	revisionService     revision.Service
+	reviewService       review.Service
+	workflowService     workflow.Service
+	scheduleService     schedule.Service
+	tagService          tag.Service
+	blogsService        blogs.Service
+	logger              *slog.Logger
 }
 
-func NewService(db mongo.Database, authService auth.Service) Service {
+func NewService(
+	db mongo.Database,
+	authService auth.Service,
+	eventService event.Service,
+	cacheService cache.Service,
+	autocompleteService autocomplete.Service,
+	revisionService revision.Service,
+	reviewService review.Service,
+	workflowService workflow.Service,
+	scheduleService schedule.Service,
+	tagService tag.Service,
+	blogsService blogs.Service,
+	logger *slog.Logger,
+) Service {
 	return &service{
-		blogQueryBuilder: mongo.NewQueryBuilder[model.Blog](db, model.CollectionName),
-		authService:      authService,
+		blogQueryBuilder:    mongo.NewQueryBuilder[model.Blog](db, model.CollectionName),
+		authService:         authService,
+		eventService:        eventService,
+		cacheService:        cacheService,
+		autocompleteService: autocompleteService,
+		revisionService:     revisionService,
+		reviewService:       reviewService,
+		workflowService:     workflowService,
+		scheduleService:     scheduleService,
+		tagService:          tagService,
+		blogsService:        blogsService,
+		logger:              logger,
 	}
 }
 
-func (s *service) BlogPublication(blogId primitive.ObjectID, editor *message.User, publish bool) error {
-	filter := bson.M{"_id": blogId, "status": true}
-	blog, err := s.blogQueryBuilder.SingleQuery().FindOne(filter, nil)
-	if err != nil {
-		return network.NewNotFoundError("blog for _id "+blogId.Hex()+" not found", err)
+func (s *service) BlogPublication(ctx context.Context, blogId primitive.ObjectID, editor *message.User, publish bool, version int64) error {
+	if err := s.publication(ctx, blogId, editor, publish, &version); err != nil {
+		return err
 	}
 
+	// done by hand, what was scheduled for it is obsolete
+	action := workflowModel.ActionUnpublish
 	if publish {
-		if blog.Published {
-			return network.NewBadRequestError("blog for _id "+blogId.Hex()+" is already published", err)
-		}
-		if !blog.Submitted {
-			return network.NewBadRequestError("blog for _id "+blogId.Hex()+" is not submitted", err)
-		}
-	} else {
-		if !blog.Published {
-			return network.NewBadRequestError("blog for _id "+blogId.Hex()+" is not published", err)
-		}
+		action = workflowModel.ActionPublish
 	}
+	err := s.scheduleService.CancelPending(context.WithoutCancel(ctx), blogId, editor.ID, action)
+	common.FollowUp(s.logger, "editor: schedule cancellation", blogId, err)
+	return nil
+}
 
-	var update bson.M
+// publication moves the blog in or out of publication, only if it still has
+// the version when one is given. The move and its event are stored together,
+// the steps after them do not fail the publication, except for the revision
+// of a published blog.
+func (s *service) publication(ctx context.Context, blogId primitive.ObjectID, editor *message.User, publish bool, version *int64) error {
+	action := workflowModel.ActionUnpublish
+	eventType := blogv1.NATS_EVENT_BLOG_UNPUBLISHED
+	var filter, set bson.M
+	if version != nil {
+		filter = bson.M{"version": *version}
+	}
 
+	now := time.Now()
 	if publish {
+		action = workflowModel.ActionPublish
+		eventType = blogv1.NATS_EVENT_BLOG_PUBLISHED
+		// the published text is the draft as it is at the time of the move
+		set = bson.M{"text": "$draftText", "publishedAt": bson.M{"$ifNull": bson.A{"$publishedAt", now}}}
+	}
+
+	e := eventMsg.NewBlogEvent(eventType, blogId, "", editor.ID)
+	blog, err := s.workflowService.Move(ctx, blogId, action, editor, message.RoleCodeEditor, filter, set, e)
+	if err != nil {
+		return err
+	}
+
+	// the move is done, a client that goes away now must not cut the rest short
+	ctx = context.WithoutCancel(ctx)
+	s.eventService.Publish(ctx, e)
+
+	err = s.cacheService.InvalidateBlog(ctx, blog.ID, blog.Slug)
+	common.FollowUp(s.logger, "editor: cache invalidation", blog.ID, err)
+
+	var revisionErr error
+	if publish {
+		blog.Text = &blog.DraftText
 		if blog.PublishedAt == nil {
-			now := time.Now()
 			blog.PublishedAt = &now
 		}
-		update = bson.M{"drafted": false, "submitted": false, "published": true, "text": blog.DraftText, "publishedAt": blog.PublishedAt}
+		// the request fails without the revision, once the indexes are right
+		revisionErr = s.revisionService.Record(ctx, blog, revisionModel.KindPublish, editor.ID)
+		err = s.autocompleteService.IndexBlog(ctx, blog)
 	} else {
-		update = bson.M{"drafted": true, "submitted": false, "published": false}
+		err = s.autocompleteService.RemoveBlog(ctx, blog)
+	}
+	common.FollowUp(s.logger, "editor: autocomplete update", blog.ID, err)
+
+	err = s.tagService.Recount(ctx, blog.Tags...)
+	common.FollowUp(s.logger, "editor: tag recount", blog.ID, err)
+
+	err = s.blogsService.RefreshListings(ctx, blog.ID)
+	common.FollowUp(s.logger, "editor: listings refresh", blog.ID, err)
+	return revisionErr
+}
+
+// RejectBlog sends a submitted blog back to the drafts of its author, with the
+// reason as the first review of the thread. The reason is stored first, so
+// that a blog is never rejected without it, and taken back if the move fails.
+func (s *service) RejectBlog(ctx context.Context, blogId primitive.ObjectID, editor *message.User, reason string) (*reviewDto.Review, error) {
+	rejection, err := s.reviewService.AddRejection(ctx, blogId, reason, editor.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	update["updatedBy"] = editor.ID
-	update["updatedAt"] = time.Now()
+	e := eventMsg.NewBlogEvent(blogv1.NATS_EVENT_BLOG_REJECTED, blogId, "", editor.ID)
+	_, err = s.workflowService.Move(ctx, blogId, workflowModel.ActionReject, editor, message.RoleCodeEditor, nil, nil, e)
+	if err != nil {
+		removeErr := s.reviewService.RemoveReview(context.WithoutCancel(ctx), rejection.ID)
+		common.FollowUp(s.logger, "editor: rejection removal", blogId, removeErr)
+		return nil, err
+	}
+	s.eventService.Publish(ctx, e)
+
+	return rejection, nil
+}
 
-	updated := bson.M{"$set": update}
-	result, err := s.blogQueryBuilder.SingleQuery().UpdateOne(filter, updated)
+func (s *service) AddReview(ctx context.Context, blogId primitive.ObjectID, body *reviewDto.CreateReview, editor *message.User) (*reviewDto.Review, error) {
+	filter := bson.M{"_id": blogId, "state": bson.M{"$ne": model.StateDeleted}}
+	opts := options.FindOne().SetProjection(bson.D{{Key: "_id", Value: 1}})
+	_, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, opts)
+	if err != nil {
+		return nil, network.NewNotFoundError("blog for _id "+blogId.Hex()+" not found", err)
+	}
+	return s.reviewService.AddComment(ctx, blogId, body, reviewModel.RoleEditor, editor.ID)
+}
+
+// ScheduleBlog approves a submitted blog for publication at a later time, or
+// sets the time to take down a published one.
+func (s *service) ScheduleBlog(ctx context.Context, blogId primitive.ObjectID, body *scheduleDto.ScheduleBlog, editor *message.User) ([]*scheduleDto.InfoSchedule, error) {
+	now := time.Now()
+	if body.PublishAt != nil && !body.PublishAt.After(now) {
+		return nil, network.NewBadRequestError("publishAt must be in the future", nil)
+	}
+	if body.UnpublishAt != nil && !body.UnpublishAt.After(now) {
+		return nil, network.NewBadRequestError("unpublishAt must be in the future", nil)
+	}
+	if body.PublishAt != nil && body.UnpublishAt != nil && !body.UnpublishAt.After(*body.PublishAt) {
+		return nil, network.NewBadRequestError("unpublishAt must be after publishAt", nil)
+	}
+
+	if body.PublishAt == nil {
+		filter := bson.M{"_id": blogId, "state": model.StatePublished}
+		opts := options.FindOne().SetProjection(bson.D{{Key: "version", Value: 1}})
+		blog, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, opts)
+		if err != nil {
+			return nil, network.NewBadRequestError("only a published blog can be given just an unpublishAt", err)
+		}
+		if blog.Version != *body.Version {
+			return nil, dto.NewVersionConflictError(blog.Version)
+		}
+
+		// a new time replaces the one set before
+		err = s.scheduleService.CancelPending(ctx, blogId, editor.ID, workflowModel.ActionUnpublish)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	schedules := make([]*scheduleDto.InfoSchedule, 0, 2)
+
+	if body.PublishAt != nil {
+		// the move below counts as a change
+		approved := *body.Version + 1
+		schedule, err := s.scheduleService.Add(ctx, blogId, workflowModel.ActionPublish, *body.PublishAt, &approved, editor.ID)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if body.UnpublishAt != nil {
+		schedule, err := s.scheduleService.Add(ctx, blogId, workflowModel.ActionUnpublish, *body.UnpublishAt, nil, editor.ID)
+		if err != nil {
+			s.dropSchedules(ctx, schedules, editor)
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	// the schedules are stored first, so that a blog is never scheduled
+	// without them, and taken back if the move fails
+	if body.PublishAt != nil {
+		filter := bson.M{"version": *body.Version}
+		_, err := s.workflowService.Move(ctx, blogId, workflowModel.ActionSchedule, editor, message.RoleCodeEditor, filter, nil, nil)
+		if err != nil {
+			s.dropSchedules(ctx, schedules, editor)
+			return nil, err
+		}
+	}
+
+	return schedules, nil
+}
+
+// dropSchedules cancels the schedules added for a request that has failed.
+func (s *service) dropSchedules(ctx context.Context, schedules []*scheduleDto.InfoSchedule, editor *message.User) {
+	ctx = context.WithoutCancel(ctx)
+	for _, schedule := range schedules {
+		_, err := s.scheduleService.Cancel(ctx, schedule.ID, editor.ID)
+		common.FollowUp(s.logger, "editor: schedule removal", schedule.BlogID, err)
+	}
+}
+
+func (s *service) GetPaginatedScheduled(ctx context.Context, p *coredto.Pagination) ([]*scheduleDto.InfoSchedule, error) {
+	return s.scheduleService.GetPaginatedPending(ctx, p)
+}
+
+// CancelSchedule drops a pending schedule. A blog whose publication is
+// cancelled goes back to the submitted queue, together with its unpublishing,
+// which only made sense after the publication.
+func (s *service) CancelSchedule(ctx context.Context, scheduleId primitive.ObjectID, editor *message.User) error {
+	schedule, err := s.scheduleService.Cancel(ctx, scheduleId, editor.ID)
 	if err != nil {
 		return err
 	}
 
-	if result.MatchedCount == 0 {
-		return network.NewNotFoundError("blog not found", nil)
+	if schedule.Action != workflowModel.ActionPublish {
+		return nil
 	}
 
-	return nil
+	_, err = s.workflowService.Move(ctx, schedule.BlogID, workflowModel.ActionCancel, editor, message.RoleCodeEditor, nil, nil, nil)
+	// the author may have withdrawn the blog in the meantime
+	if err != nil && !common.IsClientError(err) {
+		return err
+	}
+
+	return s.scheduleService.CancelPending(ctx, schedule.BlogID, editor.ID, workflowModel.ActionUnpublish)
+}
+
+// RefreshBlog publishes the event of a change that did not go through the
+// editor, e.g. a merge of its tags, and for a published blog drops its cached
+// copies and refreshes the listings.
+func (s *service) RefreshBlog(ctx context.Context, blog *model.Blog, e *eventMsg.BlogEvent) error {
+	s.eventService.Publish(ctx, e)
+	if blog.State != model.StatePublished {
+		return nil
+	}
+
+	return errors.Join(
+		s.cacheService.InvalidateBlog(ctx, blog.ID, blog.Slug),
+		s.blogsService.RefreshListings(ctx, blog.ID),
+	)
+}
+
+// RunSchedule performs a due schedule for the scheduler, as the editor who
+// created it.
+func (s *service) RunSchedule(ctx context.Context, schedule *scheduleModel.Schedule) error {
+	editor := &message.User{ID: schedule.CreatedBy}
+	publish := schedule.Action == workflowModel.ActionPublish
+
+	if publish {
+		// an earlier run may have stopped right after the move
+		filter := bson.M{"_id": schedule.BlogID, "state": model.StatePublished}
+		opts := options.FindOne().SetProjection(bson.D{{Key: "_id", Value: 1}})
+		if _, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, opts); err == nil {
+			return nil
+		}
+	}
+
+	return s.publication(ctx, schedule.BlogID, editor, publish, schedule.Version)
 }
 
-func (s *service) GetBlogById(id primitive.ObjectID) (*authorDto.PrivateBlog, error) {
-	filter := bson.M{"_id": id, "status": true}
-	blog, err := s.blogQueryBuilder.SingleQuery().FindOne(filter, nil)
+func (s *service) GetBlogById(ctx context.Context, id primitive.ObjectID) (*authorDto.PrivateBlog, error) {
+	filter := bson.M{"_id": id, "state": bson.M{"$ne": model.StateDeleted}}
+	blog, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := s.authService.FindUserPublicProfile(ctx, blog.Author)
 	if err != nil {
 		return nil, err
 	}
 
-	author, err := s.authService.FindUserPublicProfile(blog.Author)
+	b, err := authorDto.NewPrivateBlog(blog, author)
 	if err != nil {
 		return nil, err
 	}
 
-	return authorDto.NewPrivateBlog(blog, author)
+	b.Reviews, err = s.reviewService.GetThread(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (s *service) GetPaginatedPublished(ctx context.Context, query *dto.ListQuery) (*dto.Page[*dto.InfoBlog], error) {
+	filter := bson.M{"state": model.StatePublished}
+	return s.getPaginated(ctx, filter, query)
+}
+
+func (s *service) GetPaginatedSubmitted(ctx context.Context, query *dto.ListQuery) (*dto.Page[*dto.InfoBlog], error) {
+	filter := bson.M{"state": model.StateSubmitted}
+	return s.getPaginated(ctx, filter, query)
 }
 
-func (s *service) GetPaginatedPublished(p *coredto.Pagination) ([]*dto.InfoBlog, error) {
-	filter := bson.M{"status": true, "published": true}
-	return s.getPaginated(filter, p, nil)
+func (s *service) GetPublishedByCursor(ctx context.Context, query *dto.CursorQuery) (*dto.CursorPage[*dto.InfoBlog], error) {
+	filter := bson.M{"state": model.StatePublished}
+	return s.getByCursor(ctx, filter, query)
 }
 
-func (s *service) GetPaginatedSubmitted(p *coredto.Pagination) ([]*dto.InfoBlog, error) {
-	filter := bson.M{"status": true, "submitted": true}
-	return s.getPaginated(filter, p, nil)
+func (s *service) GetSubmittedByCursor(ctx context.Context, query *dto.CursorQuery) (*dto.CursorPage[*dto.InfoBlog], error) {
+	filter := bson.M{"state": model.StateSubmitted}
+	return s.getByCursor(ctx, filter, query)
 }
 
-func (s *service) getPaginated(filter bson.M, p *coredto.Pagination, opts *options.FindOptions) ([]*dto.InfoBlog, error) {
-	blogs, err := s.blogQueryBuilder.SingleQuery().FindPaginated(filter, p.Page, p.Limit, opts)
+func (s *service) GetRevisions(ctx context.Context, blogId primitive.ObjectID, p *coredto.Pagination) ([]*revisionDto.InfoRevision, error) {
+	return s.revisionService.GetPaginated(ctx, blogId, p)
+}
+
+// DiffRevisions compares the last published revision with the latest one,
+// unless other revisions are asked for.
+func (s *service) DiffRevisions(ctx context.Context, blogId primitive.ObjectID, query *revisionDto.DiffQuery) (*revisionDto.RevisionDiff, error) {
+	return s.revisionService.Diff(ctx, blogId, query)
+}
+
+func (s *service) GetTransitions(ctx context.Context, blogId primitive.ObjectID) ([]*workflowDto.Transition, error) {
+	return s.workflowService.GetHistory(ctx, blogId)
+}
+
+func (s *service) getPaginated(ctx context.Context, filter bson.M, query *dto.ListQuery) (*dto.Page[*dto.InfoBlog], error) {
+	tags, err := s.tagService.Resolve(ctx, query.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err = query.Filter(filter, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.blogQueryBuilder.GetCollection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(query.SortOrder())
+	blogs, err := s.blogQueryBuilder.Query(ctx).FindPaginated(filter, query.Page, query.Limit, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -124,5 +430,24 @@ func (s *service) getPaginated(filter bson.M, p *coredto.Pagination, opts *optio
 		dtos[i] = d
 	}
 
-	return dtos, nil
+	return dto.NewPage(dtos, total, query.Page, query.Limit), nil
+}
+
+func (s *service) getByCursor(ctx context.Context, filter bson.M, query *dto.CursorQuery) (*dto.CursorPage[*dto.InfoBlog], error) {
+	tags, err := s.tagService.Resolve(ctx, query.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err = query.Filter(filter, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	blogs, next, err := common.FindByCursor(ctx, s.blogQueryBuilder, common.UpdatedOrder, filter, query.Cursor, query.Limit, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewCursorPage(blogs, next, dto.NewInfoBlog)
 }
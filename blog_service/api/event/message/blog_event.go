@@ -0,0 +1,44 @@
+package message
+
+import (
+	"time"
+
+	blogv1 "github.com/afteracademy/gomicro/contracts/blog/v1"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BlogEvent = blogv1.BlogEvent
+
+func NewBlogEvent(eventType string, blogId primitive.ObjectID, slug string, actor uuid.UUID) *BlogEvent {
+	return &BlogEvent{
+		ID:         uuid.New(),
+		Type:       eventType,
+		BlogID:     blogId.Hex(),
+		Slug:       slug,
+		Actor:      actor,
+		OccurredAt: time.Now(),
+	}
+}
+
+// NewPendingExpression turns the event into a value for an aggregation pipeline
+// update of the blog, which takes the slug from the blog being updated.
+func NewPendingExpression(event *BlogEvent) (bson.D, error) {
+	data, err := bson.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc bson.D
+	if err = bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	for i := range doc {
+		if doc[i].Key == "slug" {
+			doc[i].Value = "$slug"
+		}
+	}
+	return doc, nil
+}
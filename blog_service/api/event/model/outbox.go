@@ -0,0 +1,66 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/event/message"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const CollectionName = "outbox"
+
+// sent events are kept for a while to help debugging, then mongo removes them
+const sentRetention = 7 * 24 * time.Hour
+
+type Outbox struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Subject     string             `bson:"subject" validate:"required"`
+	Event       *message.BlogEvent `bson:"event" validate:"required"`
+	Attempts    int                `bson:"attempts"`
+	LockedUntil time.Time          `bson:"lockedUntil"`
+	SentAt      *time.Time         `bson:"sentAt,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" validate:"required"`
+}
+
+func NewOutbox(event *message.BlogEvent, lease time.Duration) (*Outbox, error) {
+	now := time.Now()
+	o := Outbox{
+		Subject:     event.Type,
+		Event:       event,
+		Attempts:    1,
+		LockedUntil: now.Add(lease),
+		CreatedAt:   now,
+	}
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (outbox *Outbox) Validate() error {
+	validate := validator.New()
+	return validate.Struct(outbox)
+}
+
+func (*Outbox) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{Keys: bson.D{{Key: "sentAt", Value: 1}, {Key: "lockedUntil", Value: 1}, {Key: "createdAt", Value: 1}}},
+		// an event moved from its blog twice is stored once
+		{
+			Keys:    bson.D{{Key: "event.id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "sentAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(sentRetention.Seconds())),
+		},
+	}
+
+	mongo.NewQueryBuilder[Outbox](db, CollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
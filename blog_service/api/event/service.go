@@ -0,0 +1,256 @@
+package event
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/gomicro/blog-service/api/event/message"
+	"github.com/afteracademy/gomicro/blog-service/api/event/model"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/nats-io/nats.go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Config struct {
+	RelayInterval time.Duration
+	Lease         time.Duration
+	FlushTimeout  time.Duration
+}
+
+// defaults for the Config values left at 0
+const (
+	DefaultRelayInterval = 5 * time.Second
+	DefaultLease         = 30 * time.Second
+	DefaultFlushTimeout  = 5 * time.Second
+)
+
+// sweepBatch bounds the blogs whose left over events are moved to the outbox
+// in one tick of the relay
+const sweepBatch = 100
+
+// Service publishes the blog events through an outbox. An event is written
+// to the blog by the same update as the change it tells about, so that one is
+// never stored without the other. Publish moves it to the outbox and sends
+// it, the relay retries what NATS did not take, and sweeps the events that an
+// instance left on a blog when it died before moving them.
+type Service interface {
+	Publish(ctx context.Context, event *message.BlogEvent)
+	StartRelay()
+	StopRelay()
+}
+
+type service struct {
+	outboxQueryBuilder mongo.QueryBuilder[model.Outbox]
+	blogQueryBuilder   mongo.QueryBuilder[blogModel.Blog]
+	natsClient         micro.NatsClient
+	identity           common.ServiceIdentity
+	logger             *slog.Logger
+	config             Config
+	stopRelay          context.CancelFunc
+}
+
+func NewService(db mongo.Database, natsClient micro.NatsClient, identity common.ServiceIdentity, logger *slog.Logger, config Config) Service {
+	if config.RelayInterval <= 0 {
+		config.RelayInterval = DefaultRelayInterval
+	}
+	if config.Lease <= 0 {
+		config.Lease = DefaultLease
+	}
+	if config.FlushTimeout <= 0 {
+		config.FlushTimeout = DefaultFlushTimeout
+	}
+
+	return &service{
+		outboxQueryBuilder: mongo.NewQueryBuilder[model.Outbox](db, model.CollectionName),
+		blogQueryBuilder:   mongo.NewQueryBuilder[blogModel.Blog](db, blogModel.CollectionName),
+		natsClient:         natsClient,
+		identity:           identity,
+		logger:             logger,
+		config:             config,
+	}
+}
+
+// Publish is best effort for the caller, the event is already stored with the
+// change, and what fails here is done by the relay later.
+func (s *service) Publish(ctx context.Context, event *message.BlogEvent) {
+	// the state change is already stored, a client that goes away now must not
+	// hold up the event
+	ctx = context.WithoutCancel(ctx)
+
+	// the new entry is leased to this instance, the relay only picks it up if
+	// the send below fails
+	outbox, err := s.take(ctx, event, s.config.Lease)
+	if err != nil {
+		s.logger.Warn("outbox: event left to the relay", "event", event.ID, "error", err)
+		return
+	}
+	if outbox == nil {
+		return
+	}
+
+	if err := s.send(ctx, outbox); err == nil {
+		s.markSent(ctx, outbox)
+	}
+}
+
+// take moves the event from its blog to the outbox. It returns nil when the
+// outbox already has the event, the relay has swept the blog in the meantime.
+func (s *service) take(ctx context.Context, event *message.BlogEvent, lease time.Duration) (*model.Outbox, error) {
+	blogId, err := primitive.ObjectIDFromHex(event.BlogID)
+	if err != nil {
+		return nil, err
+	}
+
+	outbox, err := model.NewOutbox(event, lease)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.outboxQueryBuilder.Query(ctx).InsertOne(outbox)
+	if err != nil && !mongod.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+
+	filter := bson.M{"_id": blogId}
+	update := bson.M{"$pull": bson.M{"pendingEvents": bson.M{"id": event.ID}}}
+	if _, err := s.blogQueryBuilder.GetCollection().UpdateOne(ctx, filter, update); err != nil {
+		return nil, err
+	}
+
+	if id == nil {
+		return nil, nil
+	}
+	outbox.ID = *id
+	return outbox, nil
+}
+
+// StartRelay delivers the pending events in the background until StopRelay is
+// called. Entries are claimed with a lease, so several instances can relay the
+// same outbox.
+func (s *service) StartRelay() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopRelay = cancel
+	go s.relay(ctx)
+}
+
+func (s *service) StopRelay() {
+	if s.stopRelay != nil {
+		s.stopRelay()
+	}
+}
+
+func (s *service) relay(ctx context.Context) {
+	ticker := time.NewTicker(s.config.RelayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+			s.relayPending(ctx)
+		}
+	}
+}
+
+func (s *service) relayPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		outbox, err := s.claim(ctx)
+		if err != nil {
+			return
+		}
+
+		if err := s.send(ctx, outbox); err != nil {
+			// NATS is still down, leave the rest for the next tick
+			s.logger.Warn("outbox relay: send failed", "event", outbox.Event.ID, "error", err)
+			return
+		}
+
+		s.markSent(ctx, outbox)
+	}
+}
+
+// sweep moves to the outbox the events that stayed on their blogs for longer
+// than a lease, an instance that wrote them has not managed to.
+func (s *service) sweep(ctx context.Context) {
+	stale := time.Now().Add(-s.config.Lease)
+	filter := bson.M{"pendingEvents.occurredat": bson.M{"$lte": stale}}
+	opts := options.Find().
+		SetProjection(bson.D{{Key: "pendingEvents", Value: 1}}).
+		SetLimit(sweepBatch)
+
+	blogs, err := s.blogQueryBuilder.Query(ctx).FindAll(filter, opts)
+	if err != nil {
+		return
+	}
+
+	for _, blog := range blogs {
+		for _, event := range blog.PendingEvents {
+			if event.OccurredAt.After(stale) {
+				continue
+			}
+			// the entry is free to claim right away
+			if _, err := s.take(ctx, event, 0); err != nil {
+				s.logger.Warn("outbox relay: sweep failed", "event", event.ID, "error", err)
+				return
+			}
+		}
+	}
+}
+
+func (s *service) claim(ctx context.Context) (*model.Outbox, error) {
+	now := time.Now()
+	filter := bson.M{"sentAt": nil, "lockedUntil": bson.M{"$lte": now}}
+	update := bson.M{
+		"$set": bson.M{"lockedUntil": now.Add(s.config.Lease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var outbox model.Outbox
+	err := s.outboxQueryBuilder.GetCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&outbox)
+	if err != nil {
+		return nil, err
+	}
+	return &outbox, nil
+}
+
+func (s *service) send(ctx context.Context, outbox *model.Outbox) error {
+	data, err := micro.MsgToJson(outbox.Event)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(outbox.Subject)
+	msg.Data = data
+	// lets JetStream drop the duplicates of an event sent more than once
+	msg.Header.Set(nats.MsgIdHdr, outbox.Event.ID.String())
+	s.identity.Sign(msg)
+
+	conn := s.natsClient.GetInstance().Conn
+	if err := conn.PublishMsg(msg); err != nil {
+		return err
+	}
+
+	// publish only buffers the message, the flush confirms that the server
+	// has received it
+	ctx, cancel := context.WithTimeout(ctx, s.config.FlushTimeout)
+	defer cancel()
+	return conn.FlushWithContext(ctx)
+}
+
+func (s *service) markSent(ctx context.Context, outbox *model.Outbox) {
+	filter := bson.M{"_id": outbox.ID}
+	update := bson.M{"$set": bson.M{"sentAt": time.Now()}}
+	s.outboxQueryBuilder.Query(ctx).UpdateOne(filter, update)
+}
@@ -0,0 +1,129 @@
+package follow
+
+import (
+	"github.com/afteracademy/gomicro/blog-service/api/follow/dto"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	coredto "github.com/afteracademy/goserve/v2/dto"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+)
+
+type controller struct {
+	micro.Controller
+	common.ContextPayload
+	service Service
+}
+
+func NewController(
+	authMFunc network.AuthenticationProvider,
+	authorizeMFunc network.AuthorizationProvider,
+	service Service,
+) micro.Controller {
+	return &controller{
+		Controller:     micro.NewController("/follow", authMFunc, authorizeMFunc),
+		ContextPayload: common.NewContextPayload(),
+		service:        service,
+	}
+}
+
+func (c *controller) MountNats(group micro.NatsGroup) {}
+
+func (c *controller) MountRoutes(group *gin.RouterGroup) {
+	group.Use(c.Authentication())
+	group.GET("/following", c.getFollowingHandler)
+	group.PUT("/author/id/:id", c.followAuthorHandler)
+	group.DELETE("/author/id/:id", c.unfollowAuthorHandler)
+	group.PUT("/tag/:tag", c.followTagHandler)
+	group.DELETE("/tag/:tag", c.unfollowTagHandler)
+}
+
+func (c *controller) getFollowingHandler(ctx *gin.Context) {
+	pagination, err := network.ReqQuery[coredto.Pagination](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	follows, err := c.service.GetPaginatedFollowing(ctx.Request.Context(), user, pagination)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", &follows)
+}
+
+func (c *controller) followAuthorHandler(ctx *gin.Context) {
+	uuidParam, err := network.ReqParams[coredto.UUID](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	err = c.service.FollowAuthor(ctx.Request.Context(), uuidParam.ID, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessMsgResponse(ctx, "author followed successfully")
+}
+
+func (c *controller) unfollowAuthorHandler(ctx *gin.Context) {
+	uuidParam, err := network.ReqParams[coredto.UUID](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	err = c.service.UnfollowAuthor(ctx.Request.Context(), uuidParam.ID, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessMsgResponse(ctx, "author unfollowed successfully")
+}
+
+func (c *controller) followTagHandler(ctx *gin.Context) {
+	tag, err := network.ReqParams[dto.Tag](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	err = c.service.FollowTag(ctx.Request.Context(), tag.Tag, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessMsgResponse(ctx, "tag followed successfully")
+}
+
+func (c *controller) unfollowTagHandler(ctx *gin.Context) {
+	tag, err := network.ReqParams[dto.Tag](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	err = c.service.UnfollowTag(ctx.Request.Context(), tag.Tag, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessMsgResponse(ctx, "tag unfollowed successfully")
+}
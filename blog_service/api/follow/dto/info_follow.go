@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/follow/model"
+)
+
+type InfoFollow struct {
+	Kind      model.Kind `json:"kind"`
+	Target    string     `json:"target"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+func NewInfoFollow(follow *model.Follow) *InfoFollow {
+	return &InfoFollow{
+		Kind:      follow.Kind,
+		Target:    follow.Target,
+		CreatedAt: follow.CreatedAt,
+	}
+}
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+type Tag struct {
+	Tag string `uri:"tag" binding:"required" validate:"required,max=100"`
+}
+
+func EmptyTag() *Tag {
+	return &Tag{}
+}
+
+func (d *Tag) GetValue() *Tag {
+	return d
+}
+
+func (d *Tag) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const CollectionName = "follows"
+
+// MaxFollows bounds what a feed query has to match against
+const MaxFollows = 1000
+
+type Kind string
+
+const (
+	KindAuthor Kind = "AUTHOR"
+	KindTag    Kind = "TAG"
+)
+
+// Follow is a reader following an author, by the author id, or a tag.
+type Follow struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	User      uuid.UUID          `bson:"user" validate:"required"`
+	Kind      Kind               `bson:"kind" validate:"required,oneof=AUTHOR TAG"`
+	Target    string             `bson:"target" validate:"required,max=100"`
+	CreatedAt time.Time          `bson:"createdAt" validate:"required"`
+}
+
+func NewFollow(user uuid.UUID, kind Kind, target string) (*Follow, error) {
+	f := Follow{
+		User:      user,
+		Kind:      kind,
+		Target:    target,
+		CreatedAt: time.Now(),
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (follow *Follow) Validate() error {
+	validate := validator.New()
+	return validate.Struct(follow)
+}
+
+func (*Follow) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user", Value: 1}, {Key: "kind", Value: 1}, {Key: "target", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "target", Value: 1}}},
+	}
+
+	mongo.NewQueryBuilder[Follow](db, CollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
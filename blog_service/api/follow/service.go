@@ -0,0 +1,218 @@
+package follow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth"
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	"github.com/afteracademy/gomicro/blog-service/api/follow/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/follow/model"
+	"github.com/afteracademy/gomicro/blog-service/api/tag"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	coredto "github.com/afteracademy/goserve/v2/dto"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/afteracademy/goserve/v2/redis"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// outlives any cached feed, see GetFeedGeneration
+const feedGenerationTTL = time.Hour
+
+// Service keeps the authors and tags each reader follows. Following and
+// unfollowing are idempotent.
+type Service interface {
+	FollowAuthor(ctx context.Context, authorId uuid.UUID, user *message.User) error
+	UnfollowAuthor(ctx context.Context, authorId uuid.UUID, user *message.User) error
+	FollowTag(ctx context.Context, tag string, user *message.User) error
+	UnfollowTag(ctx context.Context, tag string, user *message.User) error
+	GetPaginatedFollowing(ctx context.Context, user *message.User, p *coredto.Pagination) ([]*dto.InfoFollow, error)
+	GetFollowing(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, []string, error)
+	GetFeedGeneration(ctx context.Context, userId uuid.UUID, authors []uuid.UUID, tags []string) string
+	InvalidateFeeds(ctx context.Context, authorId uuid.UUID, tags []string) error
+}
+
+type service struct {
+	followQueryBuilder mongo.QueryBuilder[model.Follow]
+	store              redis.Store
+	authService        auth.Service
+	tagService         tag.Service
+}
+
+func NewService(db mongo.Database, store redis.Store, authService auth.Service, tagService tag.Service) Service {
+	return &service{
+		followQueryBuilder: mongo.NewQueryBuilder[model.Follow](db, model.CollectionName),
+		store:              store,
+		authService:        authService,
+		tagService:         tagService,
+	}
+}
+
+func (s *service) FollowAuthor(ctx context.Context, authorId uuid.UUID, user *message.User) error {
+	if authorId == user.ID {
+		return network.NewBadRequestError("you can not follow yourself", nil)
+	}
+
+	_, err := s.authService.FindUserPublicProfile(ctx, authorId)
+	if common.IsServiceUnavailable(err) {
+		return err
+	}
+	if err != nil {
+		return network.NewNotFoundError("author not found", err)
+	}
+
+	return s.follow(ctx, user, model.KindAuthor, authorId.String())
+}
+
+func (s *service) UnfollowAuthor(ctx context.Context, authorId uuid.UUID, user *message.User) error {
+	return s.unfollow(ctx, user, model.KindAuthor, authorId.String())
+}
+
+func (s *service) FollowTag(ctx context.Context, tag string, user *message.User) error {
+	return s.follow(ctx, user, model.KindTag, s.tagService.ResolveOne(ctx, tag))
+}
+
+func (s *service) UnfollowTag(ctx context.Context, tag string, user *message.User) error {
+	return s.unfollow(ctx, user, model.KindTag, s.tagService.ResolveOne(ctx, tag))
+}
+
+func (s *service) follow(ctx context.Context, user *message.User, kind model.Kind, target string) error {
+	count, err := s.followQueryBuilder.GetCollection().CountDocuments(ctx, bson.M{"user": user.ID})
+	if err != nil {
+		return err
+	}
+	if count >= model.MaxFollows {
+		return network.NewBadRequestError("you can not follow more than "+strconv.Itoa(model.MaxFollows)+" authors and tags", nil)
+	}
+
+	follow, err := model.NewFollow(user.ID, kind, target)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.followQueryBuilder.Query(ctx).InsertOne(follow)
+	if err != nil && !mongod.IsDuplicateKeyError(err) {
+		return err
+	}
+	return s.invalidateFeed(ctx, user.ID)
+}
+
+func (s *service) unfollow(ctx context.Context, user *message.User, kind model.Kind, target string) error {
+	filter := bson.M{"user": user.ID, "kind": kind, "target": target}
+	if _, err := s.followQueryBuilder.Query(ctx).DeleteOne(filter); err != nil {
+		return err
+	}
+	return s.invalidateFeed(ctx, user.ID)
+}
+
+func (s *service) GetPaginatedFollowing(ctx context.Context, user *message.User, p *coredto.Pagination) ([]*dto.InfoFollow, error) {
+	filter := bson.M{"user": user.ID}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+
+	follows, err := s.followQueryBuilder.Query(ctx).FindPaginated(filter, p.Page, p.Limit, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*dto.InfoFollow, len(follows))
+	for i, f := range follows {
+		dtos[i] = dto.NewInfoFollow(f)
+	}
+	return dtos, nil
+}
+
+// GetFollowing returns the authors and the tags the user follows.
+func (s *service) GetFollowing(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, []string, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	follows, err := s.followQueryBuilder.Query(ctx).FindAll(bson.M{"user": userId}, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	authors := []uuid.UUID{}
+	tags := []string{}
+	for _, f := range follows {
+		switch f.Kind {
+		case model.KindAuthor:
+			if id, err := uuid.Parse(f.Target); err == nil {
+				authors = append(authors, id)
+			}
+		case model.KindTag:
+			tags = append(tags, f.Target)
+		}
+	}
+	return authors, tags, nil
+}
+
+func userGenerationKey(userId uuid.UUID) string {
+	return "feed_generation_" + userId.String()
+}
+
+func targetGenerationKey(kind model.Kind, target string) string {
+	return "feed_generation_" + string(kind) + "_" + target
+}
+
+// GetFeedGeneration is part of the key of every cached feed page of the user.
+// It is a digest of the generation of what the user follows, bumped on follow
+// and unfollow, and of the generation of each followed author and tag, bumped
+// by InvalidateFeeds. A new generation was never used before, so the cached
+// pages are no longer found and expire on their own. The generations outlive
+// the pages, so that a page of an older generation is gone before a
+// generation can fall back to "0".
+func (s *service) GetFeedGeneration(ctx context.Context, userId uuid.UUID, authors []uuid.UUID, tags []string) string {
+	keys := make([]string, 0, 1+len(authors)+len(tags))
+	keys = append(keys, userGenerationKey(userId))
+	for _, a := range authors {
+		keys = append(keys, targetGenerationKey(model.KindAuthor, a.String()))
+	}
+	for _, t := range tags {
+		keys = append(keys, targetGenerationKey(model.KindTag, t))
+	}
+
+	values, err := s.store.GetInstance().MGet(ctx, keys...).Result()
+	if err != nil {
+		values = make([]any, len(keys))
+	}
+
+	hash := sha256.New()
+	for _, v := range values {
+		generation, ok := v.(string)
+		if !ok {
+			generation = "0"
+		}
+		hash.Write([]byte(generation + ","))
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+// InvalidateFeeds drops the cached feeds of all the users that follow the
+// author or any of the tags, without looking them up.
+func (s *service) InvalidateFeeds(ctx context.Context, authorId uuid.UUID, tags []string) error {
+	keys := make([]string, 0, 1+len(tags))
+	keys = append(keys, targetGenerationKey(model.KindAuthor, authorId.String()))
+	for _, t := range tags {
+		keys = append(keys, targetGenerationKey(model.KindTag, t))
+	}
+	return s.bumpGenerations(ctx, keys...)
+}
+
+func (s *service) invalidateFeed(ctx context.Context, userId uuid.UUID) error {
+	return s.bumpGenerations(ctx, userGenerationKey(userId))
+}
+
+func (s *service) bumpGenerations(ctx context.Context, keys ...string) error {
+	generation := strconv.FormatInt(time.Now().UnixNano(), 36)
+	pipe := s.store.GetInstance().Pipeline()
+	for _, key := range keys {
+		pipe.Set(ctx, key, generation, feedGenerationTTL)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
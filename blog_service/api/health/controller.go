@@ -1,6 +1,7 @@
 package health
 
 import (
+	"github.com/afteracademy/gomicro/blog-service/common"
 	"github.com/afteracademy/goserve/v2/micro"
 	"github.com/afteracademy/goserve/v2/network"
 	"github.com/gin-gonic/gin"
@@ -27,7 +28,7 @@ func (c *controller) MountRoutes(group *gin.RouterGroup) {
 func (c *controller) getHealthHandler(ctx *gin.Context) {
 	health, err := c.service.CheckHealth()
 	if err != nil {
-		network.SendMixedError(ctx, err)
+		common.SendMixedError(ctx, err)
 		return
 	}
 
@@ -5,6 +5,13 @@ import (
 )
 
 type HealthCheck struct {
-	Timestamp time.Time `json:"timestamp" binding:"required"`
-	Status    string    `json:"status" binding:"required"`
+	Timestamp time.Time       `json:"timestamp" binding:"required"`
+	Status    string          `json:"status" binding:"required"`
+	Breakers  []*BreakerState `json:"breakers,omitempty"`
+}
+
+type BreakerState struct {
+	Subject  string `json:"subject"`
+	State    string `json:"state"`
+	Failures int    `json:"failures"`
 }
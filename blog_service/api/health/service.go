@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/afteracademy/gomicro/blog-service/api/health/dto"
+	"github.com/afteracademy/gomicro/blog-service/common"
 )
 
 type Service interface {
@@ -11,10 +12,13 @@ type Service interface {
 }
 
 type service struct {
+	natsCaller common.NatsCaller
 }
 
-func NewService() Service {
-	return &service{}
+func NewService(natsCaller common.NatsCaller) Service {
+	return &service{
+		natsCaller: natsCaller,
+	}
 }
 
 func (s *service) CheckHealth() (*dto.HealthCheck, error) {
@@ -22,5 +26,19 @@ func (s *service) CheckHealth() (*dto.HealthCheck, error) {
 		Timestamp: time.Now(),
 		Status:    "OK",
 	}
+
+	// an open breaker degrades the service but does not fail the health check,
+	// the instance can still serve the requests that do not need the dependency
+	for _, b := range s.natsCaller.BreakerStates() {
+		if b.State != common.BreakerClosed {
+			health.Status = "DEGRADED"
+		}
+		health.Breakers = append(health.Breakers, &dto.BreakerState{
+			Subject:  b.Subject,
+			State:    b.State,
+			Failures: b.Failures,
+		})
+	}
+
 	return health, nil
 }
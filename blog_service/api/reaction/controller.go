@@ -0,0 +1,91 @@
+package reaction
+
+import (
+	"github.com/afteracademy/gomicro/blog-service/api/reaction/dto"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	coredto "github.com/afteracademy/goserve/v2/dto"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+)
+
+type controller struct {
+	micro.Controller
+	common.ContextPayload
+	service Service
+}
+
+func NewController(
+	authMFunc network.AuthenticationProvider,
+	authorizeMFunc network.AuthorizationProvider,
+	service Service,
+) micro.Controller {
+	return &controller{
+		Controller:     micro.NewController("/reaction", authMFunc, authorizeMFunc),
+		ContextPayload: common.NewContextPayload(),
+		service:        service,
+	}
+}
+
+func (c *controller) MountNats(group micro.NatsGroup) {}
+
+func (c *controller) MountRoutes(group *gin.RouterGroup) {
+	group.Use(c.Authentication())
+	group.GET("/blog/id/:id", c.getReactionsHandler)
+	group.PUT("/blog/id/:id/:kind", c.addReactionHandler)
+	group.DELETE("/blog/id/:id/:kind", c.removeReactionHandler)
+}
+
+func (c *controller) getReactionsHandler(ctx *gin.Context) {
+	mongoId, err := network.ReqParams[coredto.MongoId](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	reactions, err := c.service.GetReactions(ctx.Request.Context(), mongoId.ID, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "success", reactions)
+}
+
+func (c *controller) addReactionHandler(ctx *gin.Context) {
+	params, err := network.ReqParams[dto.BlogReaction](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	reactions, err := c.service.AddReaction(ctx.Request.Context(), params.ID, params.GetKind(), user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "reaction added successfully", reactions)
+}
+
+func (c *controller) removeReactionHandler(ctx *gin.Context) {
+	params, err := network.ReqParams[dto.BlogReaction](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	reactions, err := c.service.RemoveReaction(ctx.Request.Context(), params.ID, params.GetKind(), user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "reaction removed successfully", reactions)
+}
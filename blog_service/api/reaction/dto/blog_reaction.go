@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/afteracademy/gomicro/blog-service/api/reaction/model"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BlogReaction struct {
+	Id   string             `uri:"id" binding:"required" validate:"required,len=24"`
+	Kind string             `uri:"kind" binding:"required" validate:"required,oneof=like clap"`
+	ID   primitive.ObjectID `uri:"-" validate:"-"`
+}
+
+func EmptyBlogReaction() *BlogReaction {
+	return &BlogReaction{}
+}
+
+func (d *BlogReaction) GetValue() *BlogReaction {
+	id, err := mongo.NewObjectID(d.Id)
+	if err == nil {
+		d.ID = id
+	}
+	return d
+}
+
+func (d *BlogReaction) GetKind() model.Kind {
+	return model.Kind(strings.ToUpper(d.Kind))
+}
+
+func (d *BlogReaction) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
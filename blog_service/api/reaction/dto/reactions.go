@@ -0,0 +1,8 @@
+package dto
+
+type Reactions struct {
+	LikeCount int64 `json:"likeCount"`
+	ClapCount int64 `json:"clapCount"`
+	Liked     bool  `json:"liked"`
+	Clapped   bool  `json:"clapped"`
+}
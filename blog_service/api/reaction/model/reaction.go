@@ -0,0 +1,62 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const CollectionName = "reactions"
+
+type Kind string
+
+const (
+	KindLike Kind = "LIKE"
+	KindClap Kind = "CLAP"
+)
+
+// Reaction is kept once per user, blog and kind, the unique index is what
+// makes reacting twice a no-op.
+type Reaction struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	BlogID    primitive.ObjectID `bson:"blogId" validate:"required"`
+	User      uuid.UUID          `bson:"user" validate:"required"`
+	Kind      Kind               `bson:"kind" validate:"required,oneof=LIKE CLAP"`
+	CreatedAt time.Time          `bson:"createdAt" validate:"required"`
+}
+
+func NewReaction(blogId primitive.ObjectID, user uuid.UUID, kind Kind) (*Reaction, error) {
+	r := Reaction{
+		BlogID:    blogId,
+		User:      user,
+		Kind:      kind,
+		CreatedAt: time.Now(),
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (reaction *Reaction) Validate() error {
+	validate := validator.New()
+	return validate.Struct(reaction)
+}
+
+func (*Reaction) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{
+			Keys:    bson.D{{Key: "blogId", Value: 1}, {Key: "user", Value: 1}, {Key: "kind", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	mongo.NewQueryBuilder[Reaction](db, CollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
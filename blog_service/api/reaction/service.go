@@ -0,0 +1,120 @@
+package reaction
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	"github.com/afteracademy/gomicro/blog-service/api/blog"
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/gomicro/blog-service/api/reaction/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/reaction/model"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// counters are the fields of the blog that hold the count of each kind
+var counters = map[model.Kind]string{
+	model.KindLike: "likeCount",
+	model.KindClap: "clapCount",
+}
+
+// Service keeps the reactions of the users on the published blogs. Adding and
+// removing a reaction are both idempotent, repeating either leaves the blog
+// counts as they are. The counts are kept in step as best effort, the score
+// refresh recounts them from the reactions.
+type Service interface {
+	GetReactions(ctx context.Context, blogId primitive.ObjectID, user *message.User) (*dto.Reactions, error)
+	AddReaction(ctx context.Context, blogId primitive.ObjectID, kind model.Kind, user *message.User) (*dto.Reactions, error)
+	RemoveReaction(ctx context.Context, blogId primitive.ObjectID, kind model.Kind, user *message.User) (*dto.Reactions, error)
+}
+
+type service struct {
+	reactionQueryBuilder mongo.QueryBuilder[model.Reaction]
+	blogQueryBuilder     mongo.QueryBuilder[blogModel.Blog]
+	blogService          blog.Service
+	logger               *slog.Logger
+}
+
+func NewService(db mongo.Database, blogService blog.Service, logger *slog.Logger) Service {
+	return &service{
+		reactionQueryBuilder: mongo.NewQueryBuilder[model.Reaction](db, model.CollectionName),
+		blogQueryBuilder:     mongo.NewQueryBuilder[blogModel.Blog](db, blogModel.CollectionName),
+		blogService:          blogService,
+		logger:               logger,
+	}
+}
+
+func (s *service) GetReactions(ctx context.Context, blogId primitive.ObjectID, user *message.User) (*dto.Reactions, error) {
+	filter := bson.M{"_id": blogId, "state": blogModel.StatePublished}
+	opts := options.FindOne().SetProjection(bson.D{{Key: "likeCount", Value: 1}, {Key: "clapCount", Value: 1}})
+	blog, err := s.blogQueryBuilder.Query(ctx).FindOne(filter, opts)
+	if err != nil {
+		return nil, network.NewNotFoundError("blog "+blogId.Hex()+" not found", err)
+	}
+
+	reactions, err := s.reactionQueryBuilder.Query(ctx).FindAll(bson.M{"blogId": blogId, "user": user.ID}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &dto.Reactions{LikeCount: blog.LikeCount, ClapCount: blog.ClapCount}
+	for _, r := range reactions {
+		switch r.Kind {
+		case model.KindLike:
+			d.Liked = true
+		case model.KindClap:
+			d.Clapped = true
+		}
+	}
+	return d, nil
+}
+
+func (s *service) AddReaction(ctx context.Context, blogId primitive.ObjectID, kind model.Kind, user *message.User) (*dto.Reactions, error) {
+	if err := s.blogService.CheckPublished(ctx, blogId); err != nil {
+		return nil, err
+	}
+
+	reaction, err := model.NewReaction(blogId, user.ID, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.reactionQueryBuilder.Query(ctx).InsertOne(reaction)
+	if err == nil {
+		s.count(ctx, blogId, kind, 1)
+	} else if !mongod.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+
+	return s.GetReactions(ctx, blogId, user)
+}
+
+func (s *service) RemoveReaction(ctx context.Context, blogId primitive.ObjectID, kind model.Kind, user *message.User) (*dto.Reactions, error) {
+	if err := s.blogService.CheckPublished(ctx, blogId); err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"blogId": blogId, "user": user.ID, "kind": kind}
+	result, err := s.reactionQueryBuilder.Query(ctx).DeleteOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if result.DeletedCount > 0 {
+		s.count(ctx, blogId, kind, -1)
+	}
+
+	return s.GetReactions(ctx, blogId, user)
+}
+
+func (s *service) count(ctx context.Context, blogId primitive.ObjectID, kind model.Kind, delta int64) {
+	filter := bson.M{"_id": blogId}
+	update := bson.M{"$inc": bson.M{counters[kind]: delta}}
+	_, err := s.blogQueryBuilder.GetCollection().UpdateOne(context.WithoutCancel(ctx), filter, update)
+	common.FollowUp(s.logger, "reaction: count update", blogId, err)
+}
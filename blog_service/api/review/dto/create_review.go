@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreateReview struct {
+	Text     string              `json:"text" validate:"required,min=1,max=2000"`
+	ParentId *string             `json:"parentId,omitempty" validate:"omitempty,len=24,hexadecimal"`
+	ParentID *primitive.ObjectID `json:"-" validate:"-"`
+}
+
+func EmptyCreateReview() *CreateReview {
+	return &CreateReview{}
+}
+
+func (d *CreateReview) GetValue() *CreateReview {
+	if d.ParentId != nil {
+		id, err := mongo.NewObjectID(*d.ParentId)
+		if err == nil {
+			d.ParentID = &id
+		}
+	}
+	return d
+}
+
+func (d *CreateReview) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
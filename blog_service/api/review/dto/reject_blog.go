@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+type RejectBlog struct {
+	Reason string `json:"reason" validate:"required,min=3,max=2000"`
+}
+
+func EmptyRejectBlog() *RejectBlog {
+	return &RejectBlog{}
+}
+
+func (d *RejectBlog) GetValue() *RejectBlog {
+	return d
+}
+
+func (d *RejectBlog) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/review/model"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Review struct {
+	ID        primitive.ObjectID `json:"_id"`
+	Kind      model.Kind         `json:"kind"`
+	Text      string             `json:"text"`
+	Role      model.Role         `json:"role"`
+	CreatedBy uuid.UUID          `json:"createdBy"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Replies   []*Review          `json:"replies"`
+}
+
+func NewReview(review *model.Review) *Review {
+	return &Review{
+		ID:        review.ID,
+		Kind:      review.Kind,
+		Text:      review.Text,
+		Role:      review.Role,
+		CreatedBy: review.CreatedBy,
+		CreatedAt: review.CreatedAt,
+		Replies:   []*Review{},
+	}
+}
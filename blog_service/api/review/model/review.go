@@ -0,0 +1,71 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "blog_reviews"
+
+type Kind string
+
+const (
+	KindRejection Kind = "REJECTION"
+	KindComment   Kind = "COMMENT"
+)
+
+type Role string
+
+const (
+	RoleEditor Role = "EDITOR"
+	RoleAuthor Role = "AUTHOR"
+)
+
+// Review is a note on a blog between its author and the editors. A rejection
+// starts a thread, and comments reply to another review through ParentID.
+type Review struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty"`
+	BlogID    primitive.ObjectID  `bson:"blogId" validate:"required"`
+	ParentID  *primitive.ObjectID `bson:"parentId,omitempty"`
+	Kind      Kind                `bson:"kind" validate:"required"`
+	Text      string              `bson:"text" validate:"required,max=2000"`
+	Role      Role                `bson:"role" validate:"required"`
+	CreatedBy uuid.UUID           `bson:"createdBy" validate:"required"`
+	CreatedAt time.Time           `bson:"createdAt" validate:"required"`
+}
+
+func NewReview(blogId primitive.ObjectID, parentId *primitive.ObjectID, kind Kind, text string, role Role, user uuid.UUID) (*Review, error) {
+	r := Review{
+		BlogID:    blogId,
+		ParentID:  parentId,
+		Kind:      kind,
+		Text:      text,
+		Role:      role,
+		CreatedBy: user,
+		CreatedAt: time.Now(),
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (review *Review) Validate() error {
+	validate := validator.New()
+	return validate.Struct(review)
+}
+
+func (*Review) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{Keys: bson.D{{Key: "blogId", Value: 1}, {Key: "createdAt", Value: 1}}},
+	}
+
+	mongo.NewQueryBuilder[Review](db, CollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
@@ -0,0 +1,102 @@
+package review
+
+import (
+	"context"
+
+	"github.com/afteracademy/gomicro/blog-service/api/review/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/review/model"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Service interface {
+	AddRejection(ctx context.Context, blogId primitive.ObjectID, reason string, editor uuid.UUID) (*dto.Review, error)
+	AddComment(ctx context.Context, blogId primitive.ObjectID, body *dto.CreateReview, role model.Role, user uuid.UUID) (*dto.Review, error)
+	RemoveReview(ctx context.Context, reviewId primitive.ObjectID) error
+	GetThread(ctx context.Context, blogId primitive.ObjectID) ([]*dto.Review, error)
+}
+
+type service struct {
+	reviewQueryBuilder mongo.QueryBuilder[model.Review]
+}
+
+func NewService(db mongo.Database) Service {
+	return &service{
+		reviewQueryBuilder: mongo.NewQueryBuilder[model.Review](db, model.CollectionName),
+	}
+}
+
+func (s *service) AddRejection(ctx context.Context, blogId primitive.ObjectID, reason string, editor uuid.UUID) (*dto.Review, error) {
+	review, err := model.NewReview(blogId, nil, model.KindRejection, reason, model.RoleEditor, editor)
+	if err != nil {
+		return nil, err
+	}
+	return s.insert(ctx, review)
+}
+
+func (s *service) AddComment(ctx context.Context, blogId primitive.ObjectID, body *dto.CreateReview, role model.Role, user uuid.UUID) (*dto.Review, error) {
+	if body.ParentID != nil {
+		filter := bson.M{"_id": *body.ParentID, "blogId": blogId}
+		opts := options.FindOne().SetProjection(bson.D{{Key: "_id", Value: 1}})
+		_, err := s.reviewQueryBuilder.Query(ctx).FindOne(filter, opts)
+		if err != nil {
+			return nil, network.NewNotFoundError("review "+body.ParentID.Hex()+" not found for the blog", err)
+		}
+	}
+
+	review, err := model.NewReview(blogId, body.ParentID, model.KindComment, body.Text, role, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.insert(ctx, review)
+}
+
+// RemoveReview takes back a review whose action did not go through, as a
+// rejection of a blog that could not be moved.
+func (s *service) RemoveReview(ctx context.Context, reviewId primitive.ObjectID) error {
+	_, err := s.reviewQueryBuilder.GetCollection().DeleteOne(ctx, bson.M{"_id": reviewId})
+	return err
+}
+
+func (s *service) insert(ctx context.Context, review *model.Review) (*dto.Review, error) {
+	created, err := s.reviewQueryBuilder.Query(ctx).InsertAndRetrieveOne(review)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewReview(created), nil
+}
+
+// GetThread returns the reviews of the blog as trees, oldest first. A reply
+// whose parent is missing is kept at the top level.
+func (s *service) GetThread(ctx context.Context, blogId primitive.ObjectID) ([]*dto.Review, error) {
+	filter := bson.M{"blogId": blogId}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	reviews, err := s.reviewQueryBuilder.Query(ctx).FindAll(filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[primitive.ObjectID]*dto.Review, len(reviews))
+	for _, r := range reviews {
+		nodes[r.ID] = dto.NewReview(r)
+	}
+
+	thread := make([]*dto.Review, 0)
+	for _, r := range reviews {
+		node := nodes[r.ID]
+		if r.ParentID != nil {
+			if parent, ok := nodes[*r.ParentID]; ok {
+				parent.Replies = append(parent.Replies, node)
+				continue
+			}
+		}
+		thread = append(thread, node)
+	}
+
+	return thread, nil
+}
@@ -0,0 +1,103 @@
+package revision
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/afteracademy/gomicro/blog-service/api/revision/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/revision/model"
+)
+
+// texts above this many line pairs are shown as replaced instead of being
+// diffed, the table below grows with the product of both line counts
+const maxDiffCells = 4_000_000
+
+func diffFields(from, to *model.Revision) []*dto.FieldChange {
+	changes := []*dto.FieldChange{}
+	add := func(field string, a, b any) {
+		changes = append(changes, &dto.FieldChange{Field: field, From: a, To: b})
+	}
+
+	if from.Title != to.Title {
+		add("title", from.Title, to.Title)
+	}
+	if from.Description != to.Description {
+		add("description", from.Description, to.Description)
+	}
+	if from.Slug != to.Slug {
+		add("slug", from.Slug, to.Slug)
+	}
+	if !slices.Equal(from.Tags, to.Tags) {
+		add("tags", from.Tags, to.Tags)
+	}
+	if value(from.ImgURL) != value(to.ImgURL) {
+		add("imgUrl", from.ImgURL, to.ImgURL)
+	}
+
+	return changes
+}
+
+// diffLines is a line based diff built from the longest common subsequence
+// of both texts.
+func diffLines(from, to string) []*dto.DiffLine {
+	a := strings.Split(from, "\n")
+	b := strings.Split(to, "\n")
+
+	if len(a)*len(b) > maxDiffCells {
+		lines := make([]*dto.DiffLine, 0, len(a)+len(b))
+		for _, l := range a {
+			lines = append(lines, &dto.DiffLine{Op: dto.DiffDelete, Text: l})
+		}
+		for _, l := range b {
+			lines = append(lines, &dto.DiffLine{Op: dto.DiffInsert, Text: l})
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]*dto.DiffLine, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, &dto.DiffLine{Op: dto.DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, &dto.DiffLine{Op: dto.DiffDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, &dto.DiffLine{Op: dto.DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, &dto.DiffLine{Op: dto.DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, &dto.DiffLine{Op: dto.DiffInsert, Text: b[j]})
+	}
+
+	return lines
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
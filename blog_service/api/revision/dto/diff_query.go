@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+// DiffQuery selects the revisions to compare. Without From the last published
+// revision is used, and without To the latest revision.
+type DiffQuery struct {
+	From int64 `form:"from" validate:"omitempty,min=1"`
+	To   int64 `form:"to" validate:"omitempty,min=1"`
+}
+
+func EmptyDiffQuery() *DiffQuery {
+	return &DiffQuery{}
+}
+
+func (d *DiffQuery) GetValue() *DiffQuery {
+	return d
+}
+
+func (d *DiffQuery) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
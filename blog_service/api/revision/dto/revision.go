@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/revision/model"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InfoRevision struct {
+	BlogID    primitive.ObjectID `json:"blogId"`
+	Number    int64              `json:"number"`
+	Kind      model.Kind         `json:"kind"`
+	Title     string             `json:"title"`
+	Slug      string             `json:"slug"`
+	CreatedBy uuid.UUID          `json:"createdBy"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+func NewInfoRevision(revision *model.Revision) (*InfoRevision, error) {
+	return utility.MapTo[InfoRevision](revision)
+}
+
+type Revision struct {
+	BlogID      primitive.ObjectID `json:"blogId"`
+	Number      int64              `json:"number"`
+	Kind        model.Kind         `json:"kind"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	DraftText   string             `json:"draftText"`
+	Text        *string            `json:"text,omitempty"`
+	Slug        string             `json:"slug"`
+	Tags        []string           `json:"tags"`
+	ImgURL      *string            `json:"imgUrl,omitempty"`
+	CreatedBy   uuid.UUID          `json:"createdBy"`
+	CreatedAt   time.Time          `json:"createdAt"`
+}
+
+func NewRevision(revision *model.Revision) (*Revision, error) {
+	return utility.MapTo[Revision](revision)
+}
@@ -0,0 +1,27 @@
+package dto
+
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "EQUAL"
+	DiffInsert DiffOp = "INSERT"
+	DiffDelete DiffOp = "DELETE"
+)
+
+type DiffLine struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type RevisionDiff struct {
+	From      *InfoRevision  `json:"from"`
+	To        *InfoRevision  `json:"to"`
+	Changes   []*FieldChange `json:"changes"`
+	DraftText []*DiffLine    `json:"draftText"`
+}
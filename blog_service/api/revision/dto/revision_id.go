@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RevisionId struct {
+	Id     string             `uri:"id" binding:"required" validate:"required,len=24"`
+	Number int64              `uri:"number" binding:"required" validate:"required,min=1"`
+	ID     primitive.ObjectID `uri:"-" validate:"-"`
+}
+
+func EmptyRevisionId() *RevisionId {
+	return &RevisionId{}
+}
+
+func (d *RevisionId) GetValue() *RevisionId {
+	id, err := mongo.NewObjectID(d.Id)
+	if err == nil {
+		d.ID = id
+	}
+	return d
+}
+
+func (d *RevisionId) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
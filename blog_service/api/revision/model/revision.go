@@ -0,0 +1,82 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const CollectionName = "blog_revisions"
+
+type Kind string
+
+const (
+	KindCreate  Kind = "CREATE"
+	KindUpdate  Kind = "UPDATE"
+	KindPublish Kind = "PUBLISH"
+	KindRestore Kind = "RESTORE"
+)
+
+// Revision is an immutable snapshot of the editable fields of a blog, numbered
+// from 1 within each blog.
+type Revision struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	BlogID      primitive.ObjectID `bson:"blogId" validate:"required"`
+	Number      int64              `bson:"number" validate:"required,min=1"`
+	Kind        Kind               `bson:"kind" validate:"required"`
+	Title       string             `bson:"title" validate:"required,max=500"`
+	Description string             `bson:"description" validate:"required,max=2000"`
+	DraftText   string             `bson:"draftText" validate:"required"`
+	Text        *string            `bson:"text,omitempty"`
+	Slug        string             `bson:"slug" validate:"required"`
+	Tags        []string           `bson:"tags" validate:"required"`
+	ImgURL      *string            `bson:"imgUrl,omitempty"`
+	CreatedBy   uuid.UUID          `bson:"createdBy" validate:"required"`
+	CreatedAt   time.Time          `bson:"createdAt" validate:"required"`
+}
+
+func NewRevision(blog *blogModel.Blog, number int64, kind Kind, actor uuid.UUID) (*Revision, error) {
+	r := Revision{
+		BlogID:      blog.ID,
+		Number:      number,
+		Kind:        kind,
+		Title:       blog.Title,
+		Description: blog.Description,
+		DraftText:   blog.DraftText,
+		Text:        blog.Text,
+		Slug:        blog.Slug,
+		Tags:        blog.Tags,
+		ImgURL:      blog.ImgURL,
+		CreatedBy:   actor,
+		CreatedAt:   time.Now(),
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (revision *Revision) Validate() error {
+	validate := validator.New()
+	return validate.Struct(revision)
+}
+
+func (*Revision) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{
+			Keys:    bson.D{{Key: "blogId", Value: 1}, {Key: "number", Value: -1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "blogId", Value: 1}, {Key: "kind", Value: 1}, {Key: "number", Value: -1}}},
+	}
+
+	mongo.NewQueryBuilder[Revision](db, CollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
@@ -0,0 +1,160 @@
+package revision
+
+import (
+	"context"
+	"strconv"
+
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/gomicro/blog-service/api/revision/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/revision/model"
+	coredto "github.com/afteracademy/goserve/v2/dto"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// concurrent writers of the same blog race for the next number, the unique
+// index lets only one of them have it
+const recordAttempts = 3
+
+type Service interface {
+	Record(ctx context.Context, blog *blogModel.Blog, kind model.Kind, actor uuid.UUID) error
+	GetRevision(ctx context.Context, blogId primitive.ObjectID, number int64) (*model.Revision, error)
+	GetPaginated(ctx context.Context, blogId primitive.ObjectID, p *coredto.Pagination) ([]*dto.InfoRevision, error)
+	Diff(ctx context.Context, blogId primitive.ObjectID, query *dto.DiffQuery) (*dto.RevisionDiff, error)
+}
+
+type service struct {
+	revisionQueryBuilder mongo.QueryBuilder[model.Revision]
+}
+
+func NewService(db mongo.Database) Service {
+	return &service{
+		revisionQueryBuilder: mongo.NewQueryBuilder[model.Revision](db, model.CollectionName),
+	}
+}
+
+func (s *service) Record(ctx context.Context, blog *blogModel.Blog, kind model.Kind, actor uuid.UUID) error {
+	var err error
+	for attempt := 0; attempt < recordAttempts; attempt++ {
+		var number int64
+		number, err = s.nextNumber(ctx, blog.ID)
+		if err != nil {
+			return err
+		}
+
+		var revision *model.Revision
+		revision, err = model.NewRevision(blog, number, kind, actor)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.revisionQueryBuilder.Query(ctx).InsertOne(revision)
+		if !mongod.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *service) nextNumber(ctx context.Context, blogId primitive.ObjectID) (int64, error) {
+	filter := bson.M{"blogId": blogId}
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "number", Value: -1}}).
+		SetProjection(bson.D{{Key: "number", Value: 1}})
+
+	latest, err := s.revisionQueryBuilder.Query(ctx).FindOne(filter, opts)
+	if err == mongod.ErrNoDocuments {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return latest.Number + 1, nil
+}
+
+func (s *service) GetRevision(ctx context.Context, blogId primitive.ObjectID, number int64) (*model.Revision, error) {
+	filter := bson.M{"blogId": blogId, "number": number}
+	revision, err := s.revisionQueryBuilder.Query(ctx).FindOne(filter, nil)
+	if err != nil {
+		return nil, network.NewNotFoundError("revision "+strconv.FormatInt(number, 10)+" not found", err)
+	}
+	return revision, nil
+}
+
+func (s *service) GetPaginated(ctx context.Context, blogId primitive.ObjectID, p *coredto.Pagination) ([]*dto.InfoRevision, error) {
+	filter := bson.M{"blogId": blogId}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "number", Value: -1}}).
+		SetProjection(bson.D{{Key: "draftText", Value: 0}, {Key: "text", Value: 0}})
+
+	revisions, err := s.revisionQueryBuilder.Query(ctx).FindPaginated(filter, p.Page, p.Limit, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*dto.InfoRevision, len(revisions))
+
+	for i, r := range revisions {
+		d, err := dto.NewInfoRevision(r)
+		if err != nil {
+			return nil, err
+		}
+		dtos[i] = d
+	}
+
+	return dtos, nil
+}
+
+func (s *service) Diff(ctx context.Context, blogId primitive.ObjectID, query *dto.DiffQuery) (*dto.RevisionDiff, error) {
+	from, err := s.findForDiff(ctx, blogId, query.From, model.KindPublish)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.findForDiff(ctx, blogId, query.To, "")
+	if err != nil {
+		return nil, err
+	}
+
+	fromInfo, err := dto.NewInfoRevision(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toInfo, err := dto.NewInfoRevision(to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RevisionDiff{
+		From:      fromInfo,
+		To:        toInfo,
+		Changes:   diffFields(from, to),
+		DraftText: diffLines(from.DraftText, to.DraftText),
+	}, nil
+}
+
+// findForDiff returns the revision with the number, or when it is 0 the latest
+// revision of the kind, or the latest one at all when kind is empty.
+func (s *service) findForDiff(ctx context.Context, blogId primitive.ObjectID, number int64, kind model.Kind) (*model.Revision, error) {
+	if number > 0 {
+		return s.GetRevision(ctx, blogId, number)
+	}
+
+	filter := bson.M{"blogId": blogId}
+	if kind != "" {
+		filter["kind"] = kind
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "number", Value: -1}})
+
+	revision, err := s.revisionQueryBuilder.Query(ctx).FindOne(filter, opts)
+	if err != nil {
+		return nil, network.NewNotFoundError("no revision to compare", err)
+	}
+	return revision, nil
+}
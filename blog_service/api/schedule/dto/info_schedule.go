@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/schedule/model"
+	workflowModel "github.com/afteracademy/gomicro/blog-service/api/workflow/model"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InfoSchedule struct {
+	ID        primitive.ObjectID   `json:"_id"`
+	BlogID    primitive.ObjectID   `json:"blogId"`
+	Action    workflowModel.Action `json:"action"`
+	RunAt     time.Time            `json:"runAt"`
+	Version   *int64               `json:"version,omitempty"`
+	Status    model.Status         `json:"status"`
+	Error     *string              `json:"error,omitempty"`
+	CreatedBy uuid.UUID            `json:"createdBy"`
+	CreatedAt time.Time            `json:"createdAt"`
+}
+
+func NewInfoSchedule(schedule *model.Schedule) *InfoSchedule {
+	return &InfoSchedule{
+		ID:        schedule.ID,
+		BlogID:    schedule.BlogID,
+		Action:    schedule.Action,
+		RunAt:     schedule.RunAt,
+		Version:   schedule.Version,
+		Status:    schedule.Status,
+		Error:     schedule.Error,
+		CreatedBy: schedule.CreatedBy,
+		CreatedAt: schedule.CreatedAt,
+	}
+}
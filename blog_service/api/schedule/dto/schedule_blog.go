@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+// ScheduleBlog approves a submitted blog for PublishAt, and optionally takes it
+// down at UnpublishAt. A published blog can only be given an UnpublishAt.
+type ScheduleBlog struct {
+	PublishAt   *time.Time `json:"publishAt,omitempty" validate:"required_without=UnpublishAt"`
+	UnpublishAt *time.Time `json:"unpublishAt,omitempty" validate:"omitempty"`
+	Version     *int64     `json:"version,omitempty" validate:"omitempty,min=1"`
+}
+
+func EmptyScheduleBlog() *ScheduleBlog {
+	return &ScheduleBlog{}
+}
+
+func (d *ScheduleBlog) GetValue() *ScheduleBlog {
+	return d
+}
+
+func (d *ScheduleBlog) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	workflowModel "github.com/afteracademy/gomicro/blog-service/api/workflow/model"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "blog_schedules"
+
+type Status string
+
+const (
+	StatusPending   Status = "PENDING"
+	StatusDone      Status = "DONE"
+	StatusFailed    Status = "FAILED"
+	StatusCancelled Status = "CANCELLED"
+)
+
+// Schedule is a workflow action that the scheduler performs on a blog at RunAt
+// on behalf of the editor who asked for it. A publication is bound to the
+// version of the blog that the editor has approved.
+type Schedule struct {
+	ID          primitive.ObjectID   `bson:"_id,omitempty"`
+	BlogID      primitive.ObjectID   `bson:"blogId" validate:"required"`
+	Action      workflowModel.Action `bson:"action" validate:"required,oneof=PUBLISH UNPUBLISH"`
+	RunAt       time.Time            `bson:"runAt" validate:"required"`
+	Version     *int64               `bson:"version,omitempty" validate:"omitempty,min=1"`
+	Status      Status               `bson:"status" validate:"required"`
+	Attempts    int                  `bson:"attempts"`
+	LockedUntil time.Time            `bson:"lockedUntil"`
+	Error       *string              `bson:"error,omitempty"`
+	CreatedBy   uuid.UUID            `bson:"createdBy" validate:"required"`
+	UpdatedBy   uuid.UUID            `bson:"updatedBy" validate:"required"`
+	CreatedAt   time.Time            `bson:"createdAt" validate:"required"`
+	UpdatedAt   time.Time            `bson:"updatedAt" validate:"required"`
+}
+
+func NewSchedule(blogId primitive.ObjectID, action workflowModel.Action, runAt time.Time, version *int64, editor uuid.UUID) (*Schedule, error) {
+	now := time.Now()
+	s := Schedule{
+		BlogID:    blogId,
+		Action:    action,
+		RunAt:     runAt,
+		Version:   version,
+		Status:    StatusPending,
+		CreatedBy: editor,
+		UpdatedBy: editor,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (schedule *Schedule) Validate() error {
+	validate := validator.New()
+	return validate.Struct(schedule)
+}
+
+func (*Schedule) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "runAt", Value: 1}, {Key: "lockedUntil", Value: 1}}},
+		{Keys: bson.D{{Key: "blogId", Value: 1}, {Key: "status", Value: 1}}},
+	}
+
+	mongo.NewQueryBuilder[Schedule](db, CollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
@@ -0,0 +1,222 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/schedule/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/schedule/model"
+	workflowModel "github.com/afteracademy/gomicro/blog-service/api/workflow/model"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	coredto "github.com/afteracademy/goserve/v2/dto"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// a schedule that keeps failing on e.g. the database is given up after that
+const maxAttempts = 5
+
+type Config struct {
+	Interval time.Duration
+	Lease    time.Duration
+}
+
+// defaults for the Config values left at 0
+const (
+	DefaultInterval = 10 * time.Second
+	DefaultLease    = 60 * time.Second
+)
+
+// RunFunc performs a due schedule. An ApiError of the client kind means the
+// schedule can never succeed, any other error is retried.
+type RunFunc func(ctx context.Context, schedule *model.Schedule) error
+
+// Service keeps the scheduled workflow actions and runs them when they are
+// due. Due schedules are claimed with a lease, so every instance can run the
+// scheduler and each schedule is still performed by only one of them.
+type Service interface {
+	Add(ctx context.Context, blogId primitive.ObjectID, action workflowModel.Action, runAt time.Time, version *int64, editor uuid.UUID) (*dto.InfoSchedule, error)
+	GetPaginatedPending(ctx context.Context, p *coredto.Pagination) ([]*dto.InfoSchedule, error)
+	Cancel(ctx context.Context, id primitive.ObjectID, editor uuid.UUID) (*model.Schedule, error)
+	CancelPending(ctx context.Context, blogId primitive.ObjectID, editor uuid.UUID, actions ...workflowModel.Action) error
+	StartScheduler(run RunFunc)
+	StopScheduler()
+}
+
+type service struct {
+	scheduleQueryBuilder mongo.QueryBuilder[model.Schedule]
+	logger               *slog.Logger
+	config               Config
+	stopScheduler        context.CancelFunc
+}
+
+func NewService(db mongo.Database, logger *slog.Logger, config Config) Service {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.Lease <= 0 {
+		config.Lease = DefaultLease
+	}
+
+	return &service{
+		scheduleQueryBuilder: mongo.NewQueryBuilder[model.Schedule](db, model.CollectionName),
+		logger:               logger,
+		config:               config,
+	}
+}
+
+func (s *service) Add(ctx context.Context, blogId primitive.ObjectID, action workflowModel.Action, runAt time.Time, version *int64, editor uuid.UUID) (*dto.InfoSchedule, error) {
+	schedule, err := model.NewSchedule(blogId, action, runAt, version, editor)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.scheduleQueryBuilder.Query(ctx).InsertAndRetrieveOne(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewInfoSchedule(created), nil
+}
+
+func (s *service) GetPaginatedPending(ctx context.Context, p *coredto.Pagination) ([]*dto.InfoSchedule, error) {
+	filter := bson.M{"status": model.StatusPending}
+	opts := options.Find().SetSort(bson.D{{Key: "runAt", Value: 1}})
+
+	schedules, err := s.scheduleQueryBuilder.Query(ctx).FindPaginated(filter, p.Page, p.Limit, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*dto.InfoSchedule, len(schedules))
+	for i, schedule := range schedules {
+		dtos[i] = dto.NewInfoSchedule(schedule)
+	}
+
+	return dtos, nil
+}
+
+// Cancel stops a pending schedule that the scheduler has not claimed, and
+// returns it as it was.
+func (s *service) Cancel(ctx context.Context, id primitive.ObjectID, editor uuid.UUID) (*model.Schedule, error) {
+	now := time.Now()
+	filter := bson.M{"_id": id, "status": model.StatusPending, "lockedUntil": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"status": model.StatusCancelled, "updatedBy": editor, "updatedAt": now}}
+
+	var schedule model.Schedule
+	err := s.scheduleQueryBuilder.GetCollection().FindOneAndUpdate(ctx, filter, update).Decode(&schedule)
+	if err == mongod.ErrNoDocuments {
+		return nil, network.NewNotFoundError("pending schedule "+id.Hex()+" not found", nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+func (s *service) CancelPending(ctx context.Context, blogId primitive.ObjectID, editor uuid.UUID, actions ...workflowModel.Action) error {
+	filter := bson.M{"blogId": blogId, "status": model.StatusPending, "action": bson.M{"$in": actions}}
+	update := bson.M{"$set": bson.M{"status": model.StatusCancelled, "updatedBy": editor, "updatedAt": time.Now()}}
+	_, err := s.scheduleQueryBuilder.GetCollection().UpdateMany(ctx, filter, update)
+	return err
+}
+
+// StartScheduler runs the due schedules in the background until StopScheduler
+// is called.
+func (s *service) StartScheduler(run RunFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopScheduler = cancel
+	go s.schedule(ctx, run)
+}
+
+func (s *service) StopScheduler() {
+	if s.stopScheduler != nil {
+		s.stopScheduler()
+	}
+}
+
+func (s *service) schedule(ctx context.Context, run RunFunc) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runDue(ctx, run)
+		}
+	}
+}
+
+func (s *service) runDue(ctx context.Context, run RunFunc) {
+	for ctx.Err() == nil {
+		schedule, err := s.claim(ctx)
+		if err != nil {
+			return
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, s.config.Lease)
+		err = run(runCtx, schedule)
+		cancel()
+
+		s.complete(ctx, schedule, err)
+	}
+}
+
+func (s *service) claim(ctx context.Context) (*model.Schedule, error) {
+	now := time.Now()
+	filter := bson.M{"status": model.StatusPending, "runAt": bson.M{"$lte": now}, "lockedUntil": bson.M{"$lte": now}}
+	update := bson.M{
+		"$set": bson.M{"lockedUntil": now.Add(s.config.Lease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "runAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var schedule model.Schedule
+	err := s.scheduleQueryBuilder.GetCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&schedule)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// complete records the outcome of a run. A schedule whose run failed for a
+// passing reason stays pending, and is run again once its lease is over.
+func (s *service) complete(ctx context.Context, schedule *model.Schedule, err error) {
+	set := bson.M{"updatedAt": time.Now()}
+
+	switch {
+	case err == nil:
+		set["status"] = model.StatusDone
+	case common.IsClientError(err) || schedule.Attempts >= maxAttempts:
+		s.logger.Error("scheduler: schedule failed", "schedule", schedule.ID.Hex(), "error", err)
+		set["status"] = model.StatusFailed
+		set["error"] = err.Error()
+		var apiError network.ApiError
+		if errors.As(err, &apiError) {
+			set["error"] = apiError.GetMessage()
+		}
+	default:
+		s.logger.Warn("scheduler: schedule will be retried", "schedule", schedule.ID.Hex(), "error", err)
+		return
+	}
+
+	// an outcome that is not stored leaves the schedule pending, to be run
+	// again once its lease is over
+	filter := bson.M{"_id": schedule.ID}
+	_, err = s.scheduleQueryBuilder.Query(ctx).UpdateOne(filter, bson.M{"$set": set})
+	if err != nil {
+		s.logger.Error("scheduler: outcome not recorded", "schedule", schedule.ID.Hex(), "status", set["status"], "error", err)
+	}
+}
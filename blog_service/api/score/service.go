@@ -0,0 +1,174 @@
+package score
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	reactionModel "github.com/afteracademy/gomicro/blog-service/api/reaction/model"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// weights of the reactions and views, each count is taken as ln(1+n) so
+	// that the first few reactions weigh the most
+	likeWeight = 3.0
+	clapWeight = 2.0
+	viewWeight = 1.0
+	// the decayed engagement at which the score reaches 1-1/e, about 0.63
+	saturation = 10.0
+	// the score of a blog without any engagement, which is also what NewBlog
+	// starts with
+	minScore = 0.01
+)
+
+type Config struct {
+	Interval time.Duration
+	HalfLife time.Duration
+}
+
+// defaults for the Config values left at 0
+const (
+	DefaultInterval = 5 * time.Minute
+	DefaultHalfLife = 72 * time.Hour
+)
+
+// Service keeps the score of the published blogs in step with their
+// engagement and age:
+//
+//	engagement = 3·ln(1+likes) + 2·ln(1+claps) + ln(1+views)
+//	decay      = 0.5^(age/halfLife), age since publishedAt
+//	score      = max(0.01, 1 - e^(-engagement·decay/10))
+//
+// The score stays within [0.01, 1) and halves its engagement every half life,
+// so a fresh blog with some reactions ranks above an old popular one. The
+// refresh first recounts the likes and claps from the reactions, which the
+// reactions only keep in step as best effort, then updates the scores. Both
+// are done in the database and give the same result on any instance, so
+// every instance runs them without a lease.
+type Service interface {
+	Refresh(ctx context.Context) error
+	StartScorer()
+	StopScorer()
+}
+
+type service struct {
+	blogQueryBuilder mongo.QueryBuilder[blogModel.Blog]
+	logger           *slog.Logger
+	config           Config
+	stopScorer       context.CancelFunc
+}
+
+func NewService(db mongo.Database, logger *slog.Logger, config Config) Service {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.HalfLife <= 0 {
+		config.HalfLife = DefaultHalfLife
+	}
+
+	return &service{
+		blogQueryBuilder: mongo.NewQueryBuilder[blogModel.Blog](db, blogModel.CollectionName),
+		logger:           logger,
+		config:           config,
+	}
+}
+
+func (s *service) Refresh(ctx context.Context) error {
+	if err := s.recount(ctx); err != nil {
+		return err
+	}
+
+	count := func(field string, weight float64) bson.M {
+		return bson.M{"$multiply": bson.A{weight, bson.M{"$ln": bson.M{"$add": bson.A{1, bson.M{"$ifNull": bson.A{"$" + field, 0}}}}}}}
+	}
+
+	engagement := bson.M{"$add": bson.A{
+		count("likeCount", likeWeight),
+		count("clapCount", clapWeight),
+		count("viewCount", viewWeight),
+	}}
+
+	age := bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{"$$NOW", bson.M{"$ifNull": bson.A{"$publishedAt", "$createdAt"}}}}}}
+	decay := bson.M{"$pow": bson.A{0.5, bson.M{"$divide": bson.A{age, s.config.HalfLife.Milliseconds()}}}}
+
+	score := bson.M{"$max": bson.A{minScore, bson.M{"$subtract": bson.A{
+		1, bson.M{"$exp": bson.M{"$multiply": bson.A{-1 / saturation, engagement, decay}}},
+	}}}}
+
+	filter := bson.M{"state": blogModel.StatePublished}
+	update := mongod.Pipeline{{{Key: "$set", Value: bson.M{"score": score}}}}
+	_, err := s.blogQueryBuilder.GetCollection().UpdateMany(ctx, filter, update)
+	return err
+}
+
+// recount sets the like and clap counts of the published blogs to the number
+// of their reactions.
+func (s *service) recount(ctx context.Context) error {
+	kind := func(k reactionModel.Kind) bson.M {
+		return bson.M{"$size": bson.M{"$filter": bson.M{
+			"input": "$reactions",
+			"cond":  bson.M{"$eq": bson.A{"$$this.kind", k}},
+		}}}
+	}
+
+	pipeline := mongod.Pipeline{
+		{{Key: "$match", Value: bson.M{"state": blogModel.StatePublished}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         reactionModel.CollectionName,
+			"localField":   "_id",
+			"foreignField": "blogId",
+			"pipeline":     bson.A{bson.M{"$project": bson.M{"_id": 0, "kind": 1}}},
+			"as":           "reactions",
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"likeCount": kind(reactionModel.KindLike),
+			"clapCount": kind(reactionModel.KindClap),
+		}}},
+		{{Key: "$merge", Value: bson.M{
+			"into":           blogModel.CollectionName,
+			"on":             "_id",
+			"whenMatched":    "merge",
+			"whenNotMatched": "discard",
+		}}},
+	}
+
+	cursor, err := s.blogQueryBuilder.GetCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	return cursor.Close(ctx)
+}
+
+// StartScorer refreshes the scores in the background until StopScorer is
+// called.
+func (s *service) StartScorer() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopScorer = cancel
+	go s.score(ctx)
+}
+
+func (s *service) StopScorer() {
+	if s.stopScorer != nil {
+		s.stopScorer()
+	}
+}
+
+func (s *service) score(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				s.logger.Error("score: refresh failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package sitemap
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/afteracademy/gomicro/blog-service/api/sitemap/dto"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+)
+
+const contentType = "application/xml; charset=utf-8"
+
+type controller struct {
+	micro.Controller
+	service Service
+	logger  *slog.Logger
+}
+
+func NewController(
+	authMFunc network.AuthenticationProvider,
+	authorizeMFunc network.AuthorizationProvider,
+	service Service,
+	logger *slog.Logger,
+) micro.Controller {
+	return &controller{
+		Controller: micro.NewController("/sitemap", authMFunc, authorizeMFunc),
+		service:    service,
+		logger:     logger,
+	}
+}
+
+func (c *controller) MountNats(group micro.NatsGroup) {}
+
+func (c *controller) MountRoutes(group *gin.RouterGroup) {
+	group.GET("/index.xml", c.getIndexHandler)
+	group.GET("/:file", c.getSitemapHandler)
+}
+
+func (c *controller) getIndexHandler(ctx *gin.Context) {
+	index, err := c.service.GetIndex(ctx.Request.Context())
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	if common.NotModified(ctx, index.ETag, index.LastModified) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.Data(http.StatusOK, contentType, []byte(index.Body))
+}
+
+func (c *controller) getSitemapHandler(ctx *gin.Context) {
+	file, err := network.ReqParams[dto.SitemapFile](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	sitemap, err := c.service.GetSitemap(ctx.Request.Context(), file.ID)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	if common.NotModified(ctx, fileETag(sitemap), sitemap.LastMod) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.Header("Content-Type", contentType)
+	ctx.Status(http.StatusOK)
+	// the status is sent with the first bytes, a failure after them can only
+	// cut the file short
+	if err = c.service.WriteSitemap(ctx.Request.Context(), sitemap, ctx.Writer); err != nil {
+		c.logger.Warn("sitemap: write cut short", "error", err)
+	}
+}
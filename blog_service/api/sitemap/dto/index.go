@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// Index is the rendered sitemap index with the validators of conditional
+// requests.
+type Index struct {
+	Body         string
+	ETag         string
+	LastModified time.Time
+}
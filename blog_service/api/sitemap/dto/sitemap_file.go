@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SitemapFile names a sitemap file by the id of its sitemap.
+type SitemapFile struct {
+	File string             `uri:"file" binding:"required" validate:"required,len=28,endswith=.xml"`
+	ID   primitive.ObjectID `uri:"-" validate:"-"`
+}
+
+func EmptySitemapFile() *SitemapFile {
+	return &SitemapFile{}
+}
+
+func (d *SitemapFile) GetValue() *SitemapFile {
+	if id, err := mongo.NewObjectID(strings.TrimSuffix(d.File, ".xml")); err == nil {
+		d.ID = id
+	}
+	return d
+}
+
+func (d *SitemapFile) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const CollectionName = "sitemaps"
+
+// the limits of one sitemap file in the sitemaps protocol
+const (
+	MaxURLs  = 50000
+	MaxBytes = 50 * 1024 * 1024
+)
+
+// Sitemap describes a sitemap file of the published blogs with an _id from
+// Start up to the Start of the next sitemap. Only the description is stored,
+// the file is streamed from the blogs when it is asked for.
+type Sitemap struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Start     primitive.ObjectID `bson:"start"`
+	Count     int64              `bson:"count" validate:"min=0,max=50000"`
+	Size      int64              `bson:"size" validate:"min=0"`
+	LastMod   time.Time          `bson:"lastMod"`
+	CreatedAt time.Time          `bson:"createdAt" validate:"required"`
+	UpdatedAt time.Time          `bson:"updatedAt" validate:"required"`
+}
+
+func (sitemap *Sitemap) Validate() error {
+	validate := validator.New()
+	return validate.Struct(sitemap)
+}
+
+func (*Sitemap) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{
+			Keys:    bson.D{{Key: "start", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	mongo.NewQueryBuilder[Sitemap](db, CollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
@@ -0,0 +1,37 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/sitemap/model"
+)
+
+const (
+	urlsetHeader = xml.Header + `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` + "\n"
+	urlsetFooter = "</urlset>\n"
+	indexHeader  = xml.Header + `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` + "\n"
+	indexFooter  = "</sitemapindex>\n"
+)
+
+// emptySize is the size of a sitemap file without any url
+const emptySize = int64(len(urlsetHeader) + len(urlsetFooter))
+
+// writeEntry writes a <url> of a sitemap, or a <sitemap> of the index.
+func writeEntry(buf *bytes.Buffer, element string, loc string, lastmod time.Time) {
+	buf.WriteString("<" + element + "><loc>")
+	xml.EscapeText(buf, []byte(loc))
+	buf.WriteString("</loc>")
+	if !lastmod.IsZero() {
+		buf.WriteString("<lastmod>" + lastmod.UTC().Format(time.RFC3339) + "</lastmod>")
+	}
+	buf.WriteString("</" + element + ">\n")
+}
+
+// fileETag changes whenever a blog of the file is published, unpublished or
+// updated, without reading the file.
+func fileETag(sitemap *model.Sitemap) string {
+	return `"` + sitemap.ID.Hex() + "-" + strconv.FormatInt(sitemap.Count, 10) + "-" + strconv.FormatInt(sitemap.LastMod.UnixMilli(), 10) + `"`
+}
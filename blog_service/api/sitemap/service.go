@@ -0,0 +1,272 @@
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/gomicro/blog-service/api/sitemap/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/sitemap/model"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Config struct {
+	// PublicURL is where the readers reach the service through the gateway,
+	// the locations of the sitemaps start with it
+	PublicURL string
+}
+
+// Service keeps the sitemaps of the published blogs. Each sitemap covers a
+// range of blog ids, so that a change of a blog only regenerates the sitemap
+// of its range, which is split when it outgrows the protocol limits. One index
+// of 50,000 sitemaps is enough for 2.5 billion blogs.
+type Service interface {
+	GetIndex(ctx context.Context) (*dto.Index, error)
+	GetSitemap(ctx context.Context, id primitive.ObjectID) (*model.Sitemap, error)
+	WriteSitemap(ctx context.Context, sitemap *model.Sitemap, w io.Writer) error
+	Refresh(ctx context.Context, blogId primitive.ObjectID) error
+	Rebuild(ctx context.Context) (int64, error)
+}
+
+type service struct {
+	sitemapQueryBuilder mongo.QueryBuilder[model.Sitemap]
+	blogQueryBuilder    mongo.QueryBuilder[blogModel.Blog]
+	config              Config
+}
+
+func NewService(db mongo.Database, config Config) Service {
+	config.PublicURL = strings.TrimSuffix(config.PublicURL, "/")
+	return &service{
+		sitemapQueryBuilder: mongo.NewQueryBuilder[model.Sitemap](db, model.CollectionName),
+		blogQueryBuilder:    mongo.NewQueryBuilder[blogModel.Blog](db, blogModel.CollectionName),
+		config:              config,
+	}
+}
+
+// GetIndex lists the sitemaps, they are generated on the first call.
+func (s *service) GetIndex(ctx context.Context) (*dto.Index, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "start", Value: 1}})
+	sitemaps, err := s.sitemapQueryBuilder.Query(ctx).FindAll(bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sitemaps) == 0 {
+		if _, err = s.Rebuild(ctx); err != nil {
+			return nil, err
+		}
+		sitemaps, err = s.sitemapQueryBuilder.Query(ctx).FindAll(bson.M{}, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	var lastMod time.Time
+	buf.WriteString(indexHeader)
+	for _, sm := range sitemaps {
+		writeEntry(&buf, "sitemap", s.config.PublicURL+"/sitemap/"+sm.ID.Hex()+".xml", sm.LastMod)
+		if sm.LastMod.After(lastMod) {
+			lastMod = sm.LastMod
+		}
+	}
+	buf.WriteString(indexFooter)
+
+	sum := sha256.Sum256(buf.Bytes())
+	return &dto.Index{
+		Body:         buf.String(),
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: lastMod,
+	}, nil
+}
+
+func (s *service) GetSitemap(ctx context.Context, id primitive.ObjectID) (*model.Sitemap, error) {
+	sitemap, err := s.sitemapQueryBuilder.Query(ctx).FindOne(bson.M{"_id": id}, nil)
+	if err != nil {
+		return nil, network.NewNotFoundError("sitemap not found", err)
+	}
+	return sitemap, nil
+}
+
+// WriteSitemap streams the urls of the range of the sitemap from the blogs.
+func (s *service) WriteSitemap(ctx context.Context, sitemap *model.Sitemap, w io.Writer) error {
+	end, err := s.nextStart(ctx, sitemap.Start)
+	if err != nil {
+		return err
+	}
+
+	// the range may have grown since it was last refreshed
+	cursor, err := s.findRange(ctx, sitemap.Start, end, model.MaxURLs)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	bw := bufio.NewWriter(w)
+	var buf bytes.Buffer
+	var size int64
+	bw.WriteString(urlsetHeader)
+	for cursor.Next(ctx) {
+		var blog blogModel.Blog
+		if err = cursor.Decode(&blog); err != nil {
+			return err
+		}
+		buf.Reset()
+		writeEntry(&buf, "url", s.blogURL(blog.Slug), blog.UpdatedAt)
+		if size += int64(buf.Len()); size+emptySize > model.MaxBytes {
+			break
+		}
+		if _, err = bw.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err = cursor.Err(); err != nil {
+		return err
+	}
+	bw.WriteString(urlsetFooter)
+	return bw.Flush()
+}
+
+// Refresh regenerates the sitemap of the range the blog falls in, after its
+// publication state, slug or content changed.
+func (s *service) Refresh(ctx context.Context, blogId primitive.ObjectID) error {
+	opts := options.FindOne().SetSort(bson.D{{Key: "start", Value: -1}})
+	sitemap, err := s.sitemapQueryBuilder.Query(ctx).FindOne(bson.M{"start": bson.M{"$lte": blogId}}, opts)
+	if err == mongod.ErrNoDocuments {
+		_, err = s.Rebuild(ctx)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	end, err := s.nextStart(ctx, sitemap.Start)
+	if err != nil {
+		return err
+	}
+	_, err = s.refresh(ctx, sitemap.Start, end)
+	return err
+}
+
+// Rebuild regenerates all the sitemaps as one range, e.g. for the blogs that
+// were published before the sitemaps existed.
+func (s *service) Rebuild(ctx context.Context) (int64, error) {
+	return s.refresh(ctx, primitive.NilObjectID, nil)
+}
+
+// refresh splits the published blogs from start up to end into sitemaps within
+// the protocol limits, and replaces the sitemaps of the range with them.
+func (s *service) refresh(ctx context.Context, start primitive.ObjectID, end *primitive.ObjectID) (int64, error) {
+	cursor, err := s.findRange(ctx, start, end, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	current := &model.Sitemap{Start: start, Size: emptySize}
+	sitemaps := []*model.Sitemap{current}
+	var buf bytes.Buffer
+	for cursor.Next(ctx) {
+		var blog blogModel.Blog
+		if err = cursor.Decode(&blog); err != nil {
+			return 0, err
+		}
+		buf.Reset()
+		writeEntry(&buf, "url", s.blogURL(blog.Slug), blog.UpdatedAt)
+		size := int64(buf.Len())
+		if current.Count == model.MaxURLs || current.Size+size > model.MaxBytes {
+			current = &model.Sitemap{Start: blog.ID, Size: emptySize}
+			sitemaps = append(sitemaps, current)
+		}
+		current.Count++
+		current.Size += size
+		if blog.UpdatedAt.After(current.LastMod) {
+			current.LastMod = blog.UpdatedAt
+		}
+	}
+	if err = cursor.Err(); err != nil {
+		return 0, err
+	}
+
+	// a range left without blogs joins the one before it, the first range is
+	// kept so that the index always has a sitemap
+	if current.Count == 0 && !start.IsZero() {
+		bounds := bson.M{"$gte": start}
+		if end != nil {
+			bounds["$lt"] = *end
+		}
+		_, err = s.sitemapQueryBuilder.GetCollection().DeleteMany(ctx, bson.M{"start": bounds})
+		return 0, err
+	}
+
+	now := time.Now()
+	starts := make(bson.A, len(sitemaps))
+	for i, sm := range sitemaps {
+		update := bson.M{
+			"$set":         bson.M{"count": sm.Count, "size": sm.Size, "lastMod": sm.LastMod, "updatedAt": now},
+			"$setOnInsert": bson.M{"createdAt": now},
+		}
+		opts := options.Update().SetUpsert(true)
+		if _, err = s.sitemapQueryBuilder.GetCollection().UpdateOne(ctx, bson.M{"start": sm.Start}, update, opts); err != nil {
+			return 0, err
+		}
+		starts[i] = sm.Start
+	}
+
+	// the sitemaps of the range that the new split leaves out
+	bounds := bson.M{"$gt": start, "$nin": starts}
+	if end != nil {
+		bounds["$lt"] = *end
+	}
+	if _, err = s.sitemapQueryBuilder.GetCollection().DeleteMany(ctx, bson.M{"start": bounds}); err != nil {
+		return 0, err
+	}
+
+	return int64(len(sitemaps)), nil
+}
+
+func (s *service) findRange(ctx context.Context, start primitive.ObjectID, end *primitive.ObjectID, limit int64) (*mongod.Cursor, error) {
+	ids := bson.M{"$gte": start}
+	if end != nil {
+		ids["$lt"] = *end
+	}
+	filter := bson.M{"_id": ids, "state": blogModel.StatePublished}
+
+	opts := options.Find().
+		SetProjection(bson.D{{Key: "slug", Value: 1}, {Key: "updatedAt", Value: 1}}).
+		SetSort(bson.D{{Key: "_id", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	return s.blogQueryBuilder.GetCollection().Find(ctx, filter, opts)
+}
+
+// nextStart is the start of the sitemap after the one from start, nil for the
+// last one.
+func (s *service) nextStart(ctx context.Context, start primitive.ObjectID) (*primitive.ObjectID, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "start", Value: 1}})
+	next, err := s.sitemapQueryBuilder.Query(ctx).FindOne(bson.M{"start": bson.M{"$gt": start}}, opts)
+	if err == mongod.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &next.Start, nil
+}
+
+func (s *service) blogURL(slug string) string {
+	return s.config.PublicURL + "/blog/slug/" + url.PathEscape(slug)
+}
@@ -0,0 +1,91 @@
+package syndication
+
+import (
+	"net/http"
+
+	"github.com/afteracademy/gomicro/blog-service/api/syndication/dto"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+)
+
+type controller struct {
+	micro.Controller
+	service Service
+}
+
+func NewController(
+	authMFunc network.AuthenticationProvider,
+	authorizeMFunc network.AuthorizationProvider,
+	service Service,
+) micro.Controller {
+	return &controller{
+		Controller: micro.NewController("/syndication", authMFunc, authorizeMFunc),
+		service:    service,
+	}
+}
+
+func (c *controller) MountNats(group micro.NatsGroup) {}
+
+func (c *controller) MountRoutes(group *gin.RouterGroup) {
+	group.GET("/latest/:format", c.getLatestFeedHandler)
+	group.GET("/tag/:tag/:format", c.getTaggedFeedHandler)
+	group.GET("/author/id/:id/:format", c.getAuthorFeedHandler)
+}
+
+func (c *controller) getLatestFeedHandler(ctx *gin.Context) {
+	params, err := network.ReqParams[dto.FeedFormat](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	doc, err := c.service.GetLatestFeed(ctx.Request.Context(), params.Format)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	sendDocument(ctx, doc)
+}
+
+func (c *controller) getTaggedFeedHandler(ctx *gin.Context) {
+	params, err := network.ReqParams[dto.TagFeed](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	doc, err := c.service.GetTaggedFeed(ctx.Request.Context(), params.Tag, params.Format)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	sendDocument(ctx, doc)
+}
+
+func (c *controller) getAuthorFeedHandler(ctx *gin.Context) {
+	params, err := network.ReqParams[dto.AuthorFeed](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	doc, err := c.service.GetAuthorFeed(ctx.Request.Context(), params.ID, params.Format)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	sendDocument(ctx, doc)
+}
+
+func sendDocument(ctx *gin.Context, doc *dto.Document) {
+	if common.NotModified(ctx, doc.ETag, doc.LastModified) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.Data(http.StatusOK, doc.ContentType, []byte(doc.Body))
+}
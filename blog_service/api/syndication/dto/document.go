@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// Document is a rendered feed with the validators of conditional requests.
+type Document struct {
+	Body         string    `json:"body"`
+	ContentType  string    `json:"contentType"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"lastModified"`
+}
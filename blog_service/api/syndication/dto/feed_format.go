@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
+type FeedFormat struct {
+	Format string `uri:"format" binding:"required" validate:"required,oneof=rss atom json"`
+}
+
+func EmptyFeedFormat() *FeedFormat {
+	return &FeedFormat{}
+}
+
+func (d *FeedFormat) GetValue() *FeedFormat {
+	return d
+}
+
+func (d *FeedFormat) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
+
+type TagFeed struct {
+	Tag    string `uri:"tag" binding:"required" validate:"required,min=1,max=100"`
+	Format string `uri:"format" binding:"required" validate:"required,oneof=rss atom json"`
+}
+
+func EmptyTagFeed() *TagFeed {
+	return &TagFeed{}
+}
+
+func (d *TagFeed) GetValue() *TagFeed {
+	return d
+}
+
+func (d *TagFeed) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
+
+type AuthorFeed struct {
+	Id     string    `uri:"id" binding:"required" validate:"required,uuid"`
+	Format string    `uri:"format" binding:"required" validate:"required,oneof=rss atom json"`
+	ID     uuid.UUID `uri:"-" validate:"-"`
+}
+
+func EmptyAuthorFeed() *AuthorFeed {
+	return &AuthorFeed{}
+}
+
+func (d *AuthorFeed) GetValue() *AuthorFeed {
+	if id, err := uuid.Parse(d.Id); err == nil {
+		d.ID = id
+	}
+	return d
+}
+
+func (d *AuthorFeed) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,257 @@
+package syndication
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/syndication/dto"
+)
+
+// channel is what every format renders, whichever blogs the feed lists.
+type channel struct {
+	Title   string
+	HomeURL string
+	SelfURL string
+	Updated time.Time
+	Entries []*entry
+}
+
+// entry is identified by the link to the blog by id, which holds when the slug
+// changes
+type entry struct {
+	ID        string
+	URL       string
+	Title     string
+	Summary   string
+	Image     *string
+	Author    string
+	Tags      []string
+	Published time.Time
+	Updated   time.Time
+}
+
+var contentTypes = map[string]string{
+	dto.FormatRSS:  "application/rss+xml; charset=utf-8",
+	dto.FormatAtom: "application/atom+xml; charset=utf-8",
+	dto.FormatJSON: "application/feed+json; charset=utf-8",
+}
+
+func render(ch *channel, format string) ([]byte, error) {
+	switch format {
+	case dto.FormatAtom:
+		return renderAtom(ch)
+	case dto.FormatJSON:
+		return renderJSON(ch)
+	default:
+		return renderRSS(ch)
+	}
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string      `xml:"title"`
+	Link          string      `xml:"link"`
+	Description   string      `xml:"description"`
+	LastBuildDate string      `xml:"lastBuildDate,omitempty"`
+	AtomLink      rssAtomLink `xml:"atom:link"`
+	Items         []rssItem   `xml:"item"`
+}
+
+type rssAtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Description string   `xml:"description"`
+	Creator     string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func renderRSS(ch *channel) ([]byte, error) {
+	doc := rss{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       ch.Title,
+			Link:        ch.HomeURL,
+			Description: ch.Title,
+			AtomLink:    rssAtomLink{Href: ch.SelfURL, Rel: "self", Type: "application/rss+xml"},
+			Items:       make([]rssItem, 0, len(ch.Entries)),
+		},
+	}
+	if !ch.Updated.IsZero() {
+		doc.Channel.LastBuildDate = ch.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, e := range ch.Entries {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       e.Title,
+			Link:        e.URL,
+			GUID:        rssGUID{IsPermaLink: true, Value: e.ID},
+			Description: e.Summary,
+			Creator:     e.Author,
+			Categories:  e.Tags,
+			PubDate:     e.Published.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	return marshalXML(doc)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Summary    string         `xml:"summary"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+}
+
+func renderAtom(ch *channel) ([]byte, error) {
+	updated := ch.Updated
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+
+	doc := atomFeed{
+		ID:      ch.SelfURL,
+		Title:   ch.Title,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: ch.SelfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: ch.HomeURL, Rel: "alternate"},
+		},
+		// entries without a known author fall back to this one
+		Author:  atomAuthor{Name: ch.Title},
+		Entries: make([]atomEntry, 0, len(ch.Entries)),
+	}
+
+	for _, e := range ch.Entries {
+		a := atomEntry{
+			ID:        e.ID,
+			Title:     e.Title,
+			Link:      atomLink{Href: e.URL, Rel: "alternate"},
+			Summary:   e.Summary,
+			Published: e.Published.UTC().Format(time.RFC3339),
+			Updated:   e.Updated.UTC().Format(time.RFC3339),
+		}
+		if e.Author != "" {
+			a.Author = &atomAuthor{Name: e.Author}
+		}
+		for _, t := range e.Tags {
+			a.Categories = append(a.Categories, atomCategory{Term: t})
+		}
+		doc.Entries = append(doc.Entries, a)
+	}
+
+	return marshalXML(doc)
+}
+
+type jsonFeed struct {
+	Version     string          `json:"version"`
+	Title       string          `json:"title"`
+	HomePageURL string          `json:"home_page_url"`
+	FeedURL     string          `json:"feed_url"`
+	Items       []jsonFeedEntry `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedEntry struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	Summary       string           `json:"summary"`
+	ContentText   string           `json:"content_text"`
+	Image         *string          `json:"image,omitempty"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+func renderJSON(ch *channel) ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       ch.Title,
+		HomePageURL: ch.HomeURL,
+		FeedURL:     ch.SelfURL,
+		Items:       make([]jsonFeedEntry, 0, len(ch.Entries)),
+	}
+
+	for _, e := range ch.Entries {
+		j := jsonFeedEntry{
+			ID:            e.ID,
+			URL:           e.URL,
+			Title:         e.Title,
+			Summary:       e.Summary,
+			ContentText:   e.Summary,
+			Image:         e.Image,
+			DatePublished: e.Published.UTC().Format(time.RFC3339),
+			DateModified:  e.Updated.UTC().Format(time.RFC3339),
+			Tags:          e.Tags,
+		}
+		if e.Author != "" {
+			j.Authors = []jsonFeedAuthor{{Name: e.Author}}
+		}
+		doc.Items = append(doc.Items, j)
+	}
+
+	return json.Marshal(doc)
+}
+
+func marshalXML(doc any) ([]byte, error) {
+	data, err := xml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,175 @@
+package syndication
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth"
+	blogDto "github.com/afteracademy/gomicro/blog-service/api/blog/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/blogs"
+	blogsDto "github.com/afteracademy/gomicro/blog-service/api/blogs/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/syndication/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/tag"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	"github.com/afteracademy/goserve/v2/redis"
+	"github.com/google/uuid"
+)
+
+const feedTitle = "Blogs"
+
+type Config struct {
+	// PublicURL is where the readers reach the service through the gateway,
+	// the links of the feeds start with it
+	PublicURL string
+	// Items is the count of the latest blogs in a feed
+	Items int64
+	// CacheTTL is how long a rendered feed is served from redis
+	CacheTTL time.Duration
+}
+
+type Service interface {
+	GetLatestFeed(ctx context.Context, format string) (*dto.Document, error)
+	GetTaggedFeed(ctx context.Context, name string, format string) (*dto.Document, error)
+	GetAuthorFeed(ctx context.Context, author uuid.UUID, format string) (*dto.Document, error)
+}
+
+type service struct {
+	blogsService blogs.Service
+	authService  auth.Service
+	cache        common.ReadThrough[*dto.Document]
+	logger       *slog.Logger
+	config       Config
+}
+
+func NewService(store redis.Store, blogsService blogs.Service, authService auth.Service, logger *slog.Logger, config Config) Service {
+	config.PublicURL = strings.TrimSuffix(config.PublicURL, "/")
+	return &service{
+		blogsService: blogsService,
+		authService:  authService,
+		cache: common.NewReadThrough[*dto.Document](store, common.ReadThroughConfig{
+			TTL:         config.CacheTTL,
+			StaleTTL:    time.Minute,
+			NegativeTTL: time.Minute,
+			LockTTL:     10 * time.Second,
+			LockWait:    3 * time.Second,
+		}),
+		logger: logger,
+		config: config,
+	}
+}
+
+func (s *service) GetLatestFeed(ctx context.Context, format string) (*dto.Document, error) {
+	key := "syndication_" + format + "_latest"
+	return s.cache.Get(ctx, key, func(ctx context.Context) (*dto.Document, error) {
+		page, err := s.blogsService.GetPaginatedLatestBlogs(ctx, s.latestQuery())
+		if err != nil {
+			return nil, err
+		}
+		ch := s.newChannel(feedTitle, "/syndication/latest/"+format)
+		return s.build(ctx, ch, page.Items, format)
+	})
+}
+
+func (s *service) GetTaggedFeed(ctx context.Context, name string, format string) (*dto.Document, error) {
+	name = tag.Normalize(name)
+	key := "syndication_" + format + "_tag_" + name
+	return s.cache.Get(ctx, key, func(ctx context.Context) (*dto.Document, error) {
+		page, err := s.blogsService.GetPaginatedTaggedBlogs(ctx, name, s.latestQuery())
+		if err != nil {
+			return nil, err
+		}
+		title := fmt.Sprintf("%s tagged %s", feedTitle, name)
+		ch := s.newChannel(title, "/syndication/tag/"+url.PathEscape(name)+"/"+format)
+		return s.build(ctx, ch, page.Items, format)
+	})
+}
+
+func (s *service) GetAuthorFeed(ctx context.Context, author uuid.UUID, format string) (*dto.Document, error) {
+	key := "syndication_" + format + "_author_" + author.String()
+	return s.cache.Get(ctx, key, func(ctx context.Context) (*dto.Document, error) {
+		user, err := s.authService.FindUserPublicProfile(ctx, author)
+		if err != nil {
+			return nil, err
+		}
+		page, err := s.blogsService.GetPaginatedAuthorBlogs(ctx, author, s.latestQuery())
+		if err != nil {
+			return nil, err
+		}
+		title := fmt.Sprintf("%s by %s", feedTitle, user.Name)
+		ch := s.newChannel(title, "/syndication/author/id/"+author.String()+"/"+format)
+		return s.build(ctx, ch, page.Items, format)
+	})
+}
+
+// latestQuery is the query of the latest blogs list, newest published first.
+func (s *service) latestQuery() *blogDto.ListQuery {
+	return &blogDto.ListQuery{
+		Page:  1,
+		Limit: s.config.Items,
+		Sort:  blogDto.SortPublished,
+	}
+}
+
+func (s *service) newChannel(title string, path string) *channel {
+	return &channel{
+		Title:   title,
+		HomeURL: s.config.PublicURL + "/list/latest",
+		SelfURL: s.config.PublicURL + path,
+	}
+}
+
+func (s *service) build(ctx context.Context, ch *channel, blogs []*blogsDto.ItemBlog, format string) (*dto.Document, error) {
+	authorIds := make([]uuid.UUID, len(blogs))
+	for i, b := range blogs {
+		authorIds[i] = b.Author
+	}
+
+	// a feed without the author names beats no feed
+	authors, err := s.authService.FindUserPublicProfiles(ctx, authorIds)
+	if err != nil {
+		s.logger.Warn("syndication: authors not resolved", "error", err)
+	}
+
+	for _, b := range blogs {
+		published := b.UpdatedAt
+		if b.PublishedAt != nil {
+			published = *b.PublishedAt
+		}
+		e := &entry{
+			ID:        s.config.PublicURL + "/blog/id/" + b.ID.Hex(),
+			URL:       s.config.PublicURL + "/blog/slug/" + url.PathEscape(b.Slug),
+			Title:     b.Title,
+			Summary:   b.Description,
+			Image:     b.ImgURL,
+			Tags:      b.Tags,
+			Published: published,
+			Updated:   b.UpdatedAt,
+		}
+		if a, ok := authors[b.Author]; ok {
+			e.Author = a.Name
+		}
+		if b.UpdatedAt.After(ch.Updated) {
+			ch.Updated = b.UpdatedAt
+		}
+		ch.Entries = append(ch.Entries, e)
+	}
+
+	body, err := render(ch, format)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	return &dto.Document{
+		Body:         string(body),
+		ContentType:  contentTypes[format],
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: ch.Updated,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package tag
+
+import (
+	"fmt"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	"github.com/afteracademy/gomicro/blog-service/api/tag/dto"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+)
+
+type controller struct {
+	micro.Controller
+	common.ContextPayload
+	service Service
+}
+
+func NewController(
+	authMFunc network.AuthenticationProvider,
+	authorizeMFunc network.AuthorizationProvider,
+	service Service,
+) micro.Controller {
+	return &controller{
+		Controller:     micro.NewController("/tag", authMFunc, authorizeMFunc),
+		ContextPayload: common.NewContextPayload(),
+		service:        service,
+	}
+}
+
+func (c *controller) MountNats(group micro.NatsGroup) {}
+
+func (c *controller) MountRoutes(group *gin.RouterGroup) {
+	group.Use(c.Authentication(), c.Authorization(string(message.RoleCodeAdmin)))
+	group.PUT("/merge", c.mergeTagsHandler)
+	group.PUT("/rename", c.renameTagHandler)
+	group.PUT("/recount", c.recountTagsHandler)
+}
+
+func (c *controller) mergeTagsHandler(ctx *gin.Context) {
+	body, err := network.ReqBody[dto.MergeTags](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	tag, err := c.service.Merge(ctx.Request.Context(), body.From, body.Into, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "tags merged successfully", tag)
+}
+
+func (c *controller) renameTagHandler(ctx *gin.Context) {
+	body, err := network.ReqBody[dto.RenameTag](ctx)
+	if err != nil {
+		network.SendBadRequestError(ctx, err.Error(), err)
+		return
+	}
+
+	user := c.MustGetUser(ctx)
+
+	tag, err := c.service.Rename(ctx.Request.Context(), body.From, body.To, user)
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessDataResponse(ctx, "tag renamed successfully", tag)
+}
+
+func (c *controller) recountTagsHandler(ctx *gin.Context) {
+	count, err := c.service.RecountAll(ctx.Request.Context())
+	if err != nil {
+		common.SendMixedError(ctx, err)
+		return
+	}
+
+	network.SendSuccessMsgResponse(ctx, fmt.Sprintf("%d tags recounted successfully", count))
+}
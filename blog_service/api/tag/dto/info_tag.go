@@ -0,0 +1,21 @@
+package dto
+
+import "github.com/afteracademy/gomicro/blog-service/api/tag/model"
+
+type InfoTag struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+	Count   int64    `json:"count"`
+}
+
+func NewInfoTag(tag *model.Tag) *InfoTag {
+	aliases := tag.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return &InfoTag{
+		Name:    tag.Name,
+		Aliases: aliases,
+		Count:   tag.Count,
+	}
+}
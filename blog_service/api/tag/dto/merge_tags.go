@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+// MergeTags replaces each of From with Into on every blog, From become the
+// aliases of Into.
+type MergeTags struct {
+	From []string `json:"from" validate:"required,min=1,max=20,dive,required,max=100"`
+	Into string   `json:"into" validate:"required,max=100"`
+}
+
+func EmptyMergeTags() *MergeTags {
+	return &MergeTags{}
+}
+
+func (d *MergeTags) GetValue() *MergeTags {
+	return d
+}
+
+func (d *MergeTags) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
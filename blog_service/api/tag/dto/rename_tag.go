@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+type RenameTag struct {
+	From string `json:"from" validate:"required,max=100"`
+	To   string `json:"to" validate:"required,max=100"`
+}
+
+func EmptyRenameTag() *RenameTag {
+	return &RenameTag{}
+}
+
+func (d *RenameTag) GetValue() *RenameTag {
+	return d
+}
+
+func (d *RenameTag) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"github.com/afteracademy/goserve/v2/utility"
+	"github.com/go-playground/validator/v10"
+)
+
+type TagQuery struct {
+	Prefix string `form:"q" validate:"omitempty,max=100"`
+	Page   int64  `form:"page" binding:"required" validate:"required,min=1,max=1000"`
+	Limit  int64  `form:"limit" binding:"required" validate:"required,min=1,max=100"`
+}
+
+func EmptyTagQuery() *TagQuery {
+	return &TagQuery{}
+}
+
+func (d *TagQuery) GetValue() *TagQuery {
+	return d
+}
+
+func (d *TagQuery) ValidateErrors(errs validator.ValidationErrors) ([]string, error) {
+	return utility.FormatValidationErrors(errs), nil
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const CollectionName = "tags"
+
+// Tag is a canonical tag. Its aliases are other spellings that are replaced
+// by the name wherever a tag is written or queried. Count is the number of
+// published blogs with the tag.
+type Tag struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Name      string             `bson:"name" validate:"required,max=100"`
+	Aliases   []string           `bson:"aliases" validate:"dive,max=100"`
+	Count     int64              `bson:"count" validate:"min=0"`
+	CreatedAt time.Time          `bson:"createdAt" validate:"required"`
+	UpdatedAt time.Time          `bson:"updatedAt" validate:"required"`
+}
+
+func (tag *Tag) Validate() error {
+	validate := validator.New()
+	return validate.Struct(tag)
+}
+
+func (*Tag) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "aliases", Value: 1}}},
+		{Keys: bson.D{{Key: "count", Value: -1}, {Key: "name", Value: 1}}},
+	}
+
+	mongo.NewQueryBuilder[Tag](db, CollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
@@ -0,0 +1,425 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	"github.com/afteracademy/gomicro/blog-service/api/autocomplete"
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	eventMsg "github.com/afteracademy/gomicro/blog-service/api/event/message"
+	followModel "github.com/afteracademy/gomicro/blog-service/api/follow/model"
+	"github.com/afteracademy/gomicro/blog-service/api/revision"
+	revisionModel "github.com/afteracademy/gomicro/blog-service/api/revision/model"
+	"github.com/afteracademy/gomicro/blog-service/api/tag/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/tag/model"
+	"github.com/afteracademy/gomicro/blog-service/common"
+	blogv1 "github.com/afteracademy/gomicro/contracts/blog/v1"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"go.mongodb.org/mongo-driver/bson"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var separators = regexp.MustCompile(`[\s_]+`)
+
+// Normalize writes a tag the one way it is stored, e.g. " go_lang " is
+// "GO-LANG". Aliases are not resolved.
+func Normalize(tag string) string {
+	return separators.ReplaceAllString(strings.ToUpper(strings.TrimSpace(tag)), "-")
+}
+
+// RefreshFunc follows up a change of the tags of a blog, which is stored with
+// its event: it publishes the event, and brings what is derived from a
+// published blog, e.g. its cached copies and the listings, up to the change.
+type RefreshFunc func(ctx context.Context, blog *blogModel.Blog, event *eventMsg.BlogEvent) error
+
+// Service keeps the tag registry. Tags are resolved to their canonical names
+// when a blog is written and when blogs are looked up by tag, and the count
+// of published blogs is kept per tag.
+type Service interface {
+	Resolve(ctx context.Context, tags []string) ([]string, error)
+	ResolveOne(ctx context.Context, tag string) string
+	Recount(ctx context.Context, tags ...string) error
+	RecountAll(ctx context.Context) (int, error)
+	GetPaginated(ctx context.Context, query *dto.TagQuery) ([]*dto.InfoTag, error)
+	Merge(ctx context.Context, from []string, into string, admin *message.User) (*dto.InfoTag, error)
+	Rename(ctx context.Context, from string, to string, admin *message.User) (*dto.InfoTag, error)
+	OnBlogsChanged(refresh RefreshFunc)
+}
+
+type service struct {
+	tagQueryBuilder     mongo.QueryBuilder[model.Tag]
+	blogQueryBuilder    mongo.QueryBuilder[blogModel.Blog]
+	followQueryBuilder  mongo.QueryBuilder[followModel.Follow]
+	autocompleteService autocomplete.Service
+	revisionService     revision.Service
+	logger              *slog.Logger
+	refresh             RefreshFunc
+}
+
+func NewService(db mongo.Database, autocompleteService autocomplete.Service, revisionService revision.Service, logger *slog.Logger) Service {
+	return &service{
+		tagQueryBuilder:     mongo.NewQueryBuilder[model.Tag](db, model.CollectionName),
+		blogQueryBuilder:    mongo.NewQueryBuilder[blogModel.Blog](db, blogModel.CollectionName),
+		followQueryBuilder:  mongo.NewQueryBuilder[followModel.Follow](db, followModel.CollectionName),
+		autocompleteService: autocompleteService,
+		revisionService:     revisionService,
+		logger:              logger,
+	}
+}
+
+// OnBlogsChanged sets what a merge runs for each blog whose tags it has
+// replaced. The services that keep those copies are built on top of the
+// tags, so they are handed in once all of them exist.
+func (s *service) OnBlogsChanged(refresh RefreshFunc) {
+	s.refresh = refresh
+}
+
+// Resolve normalizes the tags and replaces the aliases by their tag, each tag
+// is kept once in the order it first appears.
+func (s *service) Resolve(ctx context.Context, tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	normalized := make([]string, len(tags))
+	for i, t := range tags {
+		normalized[i] = Normalize(t)
+	}
+
+	filter := bson.M{"aliases": bson.M{"$in": normalized}}
+	found, err := s.tagQueryBuilder.Query(ctx).FindAll(filter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	canonical := make(map[string]string)
+	for _, t := range found {
+		for _, alias := range t.Aliases {
+			canonical[alias] = t.Name
+		}
+	}
+
+	resolved := make([]string, 0, len(normalized))
+	for _, t := range normalized {
+		if name, ok := canonical[t]; ok {
+			t = name
+		}
+		if t != "" && !slices.Contains(resolved, t) {
+			resolved = append(resolved, t)
+		}
+	}
+	return resolved, nil
+}
+
+// ResolveOne falls back to the normalized tag when the registry can not be
+// read, a lookup by an alias then finds nothing but does not fail.
+func (s *service) ResolveOne(ctx context.Context, tag string) string {
+	resolved, err := s.Resolve(ctx, []string{tag})
+	if err != nil || len(resolved) == 0 {
+		return Normalize(tag)
+	}
+	return resolved[0]
+}
+
+// Recount sets the count of published blogs of each tag, a tag that is not
+// in the registry yet is added.
+func (s *service) Recount(ctx context.Context, tags ...string) error {
+	tags = slices.Clone(tags)
+	slices.Sort(tags)
+	for _, t := range slices.Compact(tags) {
+		filter := bson.M{"state": blogModel.StatePublished, "tags": t}
+		count, err := s.blogQueryBuilder.GetCollection().CountDocuments(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if err = s.setCount(ctx, t, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *service) setCount(ctx context.Context, name string, count int64) error {
+	now := time.Now()
+	update := bson.M{
+		"$set":         bson.M{"count": count, "updatedAt": now},
+		"$setOnInsert": bson.M{"aliases": bson.A{}, "createdAt": now},
+	}
+	opts := options.Update().SetUpsert(true)
+	_, err := s.tagQueryBuilder.GetCollection().UpdateOne(ctx, bson.M{"name": name}, update, opts)
+	return err
+}
+
+// RecountAll rebuilds the counts of every tag from the published blogs, and
+// returns the number of tags in use.
+func (s *service) RecountAll(ctx context.Context) (int, error) {
+	pipeline := mongod.Pipeline{
+		{{Key: "$match", Value: bson.M{"state": blogModel.StatePublished}}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+	}
+
+	cursor, err := s.blogQueryBuilder.GetCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var counts []struct {
+		Name  string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err = cursor.All(ctx, &counts); err != nil {
+		return 0, err
+	}
+
+	names := make([]string, len(counts))
+	for i, c := range counts {
+		if err = s.setCount(ctx, c.Name, c.Count); err != nil {
+			return 0, err
+		}
+		names[i] = c.Name
+	}
+
+	filter := bson.M{"name": bson.M{"$nin": names}}
+	update := bson.M{"$set": bson.M{"count": 0, "updatedAt": time.Now()}}
+	if _, err = s.tagQueryBuilder.GetCollection().UpdateMany(ctx, filter, update); err != nil {
+		return 0, err
+	}
+
+	return len(counts), nil
+}
+
+// GetPaginated lists the tags in use, the most used first.
+func (s *service) GetPaginated(ctx context.Context, query *dto.TagQuery) ([]*dto.InfoTag, error) {
+	filter := bson.M{"count": bson.M{"$gt": 0}}
+	if prefix := Normalize(query.Prefix); prefix != "" {
+		filter["name"] = bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "count", Value: -1}, {Key: "name", Value: 1}})
+
+	tags, err := s.tagQueryBuilder.Query(ctx).FindPaginated(filter, query.Page, query.Limit, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*dto.InfoTag, len(tags))
+	for i, t := range tags {
+		dtos[i] = dto.NewInfoTag(t)
+	}
+	return dtos, nil
+}
+
+// Merge replaces the from tags with into on every blog and every follow, and
+// keeps them as aliases of into. Each changed blog gets a new version with its
+// event and revision, the way an update by its author does, and is refreshed
+// once the merge is done. The merge fails when a revision is not recorded,
+// after the rest of it.
+func (s *service) Merge(ctx context.Context, from []string, into string, admin *message.User) (*dto.InfoTag, error) {
+	into = Normalize(into)
+	names := make([]string, 0, len(from))
+	for _, f := range from {
+		if f = Normalize(f); f != into && !slices.Contains(names, f) {
+			names = append(names, f)
+		}
+	}
+	if len(names) == 0 {
+		return nil, network.NewBadRequestError("nothing to merge into "+into, nil)
+	}
+
+	changed, err := s.replaceInBlogs(ctx, names, into, admin)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.replaceInFollows(ctx, names, into); err != nil {
+		return nil, err
+	}
+
+	merged, err := s.tagQueryBuilder.Query(ctx).FindAll(bson.M{"name": bson.M{"$in": names}}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := slices.Clone(names)
+	for _, t := range merged {
+		for _, alias := range t.Aliases {
+			if alias != into {
+				aliases = append(aliases, alias)
+			}
+		}
+	}
+
+	if _, err = s.tagQueryBuilder.GetCollection().DeleteMany(ctx, bson.M{"name": bson.M{"$in": names}}); err != nil {
+		return nil, err
+	}
+
+	// into is a name of its own now and each merged name an alias of into
+	// only, no other tag may keep them as aliases
+	taken := append(slices.Clone(names), into)
+	filter := bson.M{"aliases": bson.M{"$in": taken}}
+	if _, err = s.tagQueryBuilder.GetCollection().UpdateMany(ctx, filter, bson.M{"$pull": bson.M{"aliases": bson.M{"$in": taken}}}); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$addToSet":    bson.M{"aliases": bson.M{"$each": aliases}},
+		"$set":         bson.M{"updatedAt": now},
+		"$setOnInsert": bson.M{"count": 0, "createdAt": now},
+	}
+	opts := options.Update().SetUpsert(true)
+	if _, err = s.tagQueryBuilder.GetCollection().UpdateOne(ctx, bson.M{"name": into}, update, opts); err != nil {
+		return nil, err
+	}
+
+	if err = s.Recount(ctx, into); err != nil {
+		return nil, err
+	}
+
+	if _, err = s.autocompleteService.Rebuild(ctx); err != nil {
+		s.logger.Error("tag merge: autocomplete rebuild failed", "error", err)
+	}
+
+	var revisionErr error
+	for _, c := range changed {
+		revisionErr = errors.Join(revisionErr, c.revisionErr)
+		if s.refresh != nil {
+			common.FollowUp(s.logger, "tag merge: blog refresh", c.blog.ID, s.refresh(ctx, c.blog, c.event))
+		}
+	}
+
+	if revisionErr != nil {
+		return nil, revisionErr
+	}
+
+	tag, err := s.tagQueryBuilder.Query(ctx).FindOne(bson.M{"name": into}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewInfoTag(tag), nil
+}
+
+// Rename gives a tag a new name that is not in use, the old name becomes an
+// alias.
+func (s *service) Rename(ctx context.Context, from string, to string, admin *message.User) (*dto.InfoTag, error) {
+	from = Normalize(from)
+	if _, err := s.tagQueryBuilder.Query(ctx).FindOne(bson.M{"name": from}, nil); err != nil {
+		return nil, network.NewNotFoundError("tag "+from+" not found", err)
+	}
+
+	to = Normalize(to)
+	filter := bson.M{"$or": bson.A{bson.M{"name": to}, bson.M{"aliases": to}}}
+	if _, err := s.tagQueryBuilder.Query(ctx).FindOne(filter, nil); err == nil {
+		return nil, network.NewBadRequestError("tag "+to+" is already in use, merge into it instead", nil)
+	}
+	return s.Merge(ctx, []string{from}, to, admin)
+}
+
+// changedBlog is a blog as a merge has left it, with the event of the change
+// and what kept its revision from being recorded.
+type changedBlog struct {
+	blog        *blogModel.Blog
+	event       *eventMsg.BlogEvent
+	revisionErr error
+}
+
+// replaceInBlogs keeps the order of the tags and drops the duplicates that the
+// replacement makes. Each blog is updated with its event, and then its revision
+// is recorded.
+func (s *service) replaceInBlogs(ctx context.Context, from []string, into string, admin *message.User) ([]*changedBlog, error) {
+	replaced := bson.M{"$map": bson.M{
+		"input": "$tags",
+		"in":    bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$$this", from}}, into, "$$this"}},
+	}}
+	unique := bson.M{"$reduce": bson.M{
+		"input":        replaced,
+		"initialValue": bson.A{},
+		"in": bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{"$$this", "$$value"}},
+			"$$value",
+			bson.M{"$concatArrays": bson.A{"$$value", bson.A{"$$this"}}},
+		}},
+	}}
+
+	filter := bson.M{"tags": bson.M{"$in": from}}
+	opts := options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}})
+	blogs, err := s.blogQueryBuilder.Query(ctx).FindAll(filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]*changedBlog, 0, len(blogs))
+	for _, b := range blogs {
+		e := eventMsg.NewBlogEvent(blogv1.NATS_EVENT_BLOG_UPDATED, b.ID, "", admin.ID)
+		pending, err := eventMsg.NewPendingExpression(e)
+		if err != nil {
+			return nil, err
+		}
+
+		update := mongod.Pipeline{{{Key: "$set", Value: bson.M{
+			"tags":      unique,
+			"version":   bson.M{"$add": bson.A{"$version", 1}},
+			"updatedBy": admin.ID,
+			"updatedAt": time.Now(),
+			"pendingEvents": bson.M{"$concatArrays": bson.A{
+				bson.M{"$ifNull": bson.A{"$pendingEvents", bson.A{}}},
+				bson.A{pending},
+			}},
+		}}}}
+		filter := bson.M{"_id": b.ID, "tags": bson.M{"$in": from}}
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+		var blog blogModel.Blog
+		err = s.blogQueryBuilder.GetCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&blog)
+		if err == mongod.ErrNoDocuments {
+			// changed meanwhile by its author
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		e.Slug = blog.Slug
+
+		err = s.revisionService.Record(ctx, &blog, revisionModel.KindUpdate, admin.ID)
+		changed = append(changed, &changedBlog{blog: &blog, event: e, revisionErr: err})
+	}
+	return changed, nil
+}
+
+// replaceInFollows moves the follows of each from tag to into, a user that
+// already follows into just loses the old follow.
+func (s *service) replaceInFollows(ctx context.Context, from []string, into string) error {
+	collection := s.followQueryBuilder.GetCollection()
+	for _, f := range from {
+		opts := options.Find().SetProjection(bson.D{{Key: "user", Value: 1}})
+		following, err := s.followQueryBuilder.Query(ctx).FindAll(bson.M{"kind": followModel.KindTag, "target": into}, opts)
+		if err != nil {
+			return err
+		}
+
+		users := make(bson.A, len(following))
+		for i, follow := range following {
+			users[i] = follow.User
+		}
+
+		filter := bson.M{"kind": followModel.KindTag, "target": f, "user": bson.M{"$in": users}}
+		if _, err = collection.DeleteMany(ctx, filter); err != nil {
+			return err
+		}
+
+		filter = bson.M{"kind": followModel.KindTag, "target": f}
+		if _, err = collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"target": into}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package view
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	coredto "github.com/afteracademy/goserve/v2/dto"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/afteracademy/goserve/v2/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	pendingKey = "view_pending"
+	// a trending list is rebuilt from the buckets at most once in that time
+	trendingTTL = time.Minute
+)
+
+// takes the pending counts and clears them in one step, so that counts added
+// meanwhile wait for the next flush and no count is flushed twice
+var takeScript = goredis.NewScript(`
+local counts = redis.call("hgetall", KEYS[1])
+redis.call("del", KEYS[1])
+return counts
+`)
+
+type Config struct {
+	// DedupWindow is how long a repeated view of a visitor is not counted
+	DedupWindow   time.Duration
+	FlushInterval time.Duration
+	Windows       []Window
+}
+
+// defaults for the Config durations left at 0
+const (
+	DefaultDedupWindow   = 30 * time.Minute
+	DefaultFlushInterval = time.Minute
+)
+
+// Service counts the views of the published blogs in redis and adds them to
+// the blog viewCount every flush interval. The views of the last flush
+// interval are lost if redis loses its data. Each view also goes into an
+// hourly bucket, the trending lists are the union of the buckets in a window.
+type Service interface {
+	CountView(ctx context.Context, blogId primitive.ObjectID, visitor string) error
+	GetTrendingIds(ctx context.Context, window string, p *coredto.Pagination) ([]primitive.ObjectID, error)
+	Flush(ctx context.Context) error
+	StartFlusher()
+	StopFlusher()
+}
+
+type service struct {
+	blogQueryBuilder mongo.QueryBuilder[blogModel.Blog]
+	store            redis.Store
+	logger           *slog.Logger
+	config           Config
+	retention        time.Duration
+	stopFlusher      context.CancelFunc
+}
+
+func NewService(db mongo.Database, store redis.Store, logger *slog.Logger, config Config) Service {
+	if config.DedupWindow <= 0 {
+		config.DedupWindow = DefaultDedupWindow
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultFlushInterval
+	}
+
+	retention := time.Duration(0)
+	for _, w := range config.Windows {
+		retention = max(retention, w.Length)
+	}
+
+	return &service{
+		blogQueryBuilder: mongo.NewQueryBuilder[blogModel.Blog](db, blogModel.CollectionName),
+		store:            store,
+		logger:           logger,
+		config:           config,
+		retention:        retention + time.Hour,
+	}
+}
+
+func bucketKey(t time.Time) string {
+	return "trending_bucket_" + t.UTC().Format("2006010215")
+}
+
+// CountView counts the view unless the visitor has viewed the blog within the
+// dedup window. The visitor is only kept as a hash.
+func (s *service) CountView(ctx context.Context, blogId primitive.ObjectID, visitor string) error {
+	client := s.store.GetInstance()
+
+	hash := sha256.Sum256([]byte(visitor))
+	seenKey := "view_seen_" + blogId.Hex() + "_" + hex.EncodeToString(hash[:16])
+	first, err := client.SetNX(ctx, seenKey, 1, s.config.DedupWindow).Result()
+	if err != nil || !first {
+		return err
+	}
+
+	bucket := bucketKey(time.Now())
+	pipe := client.TxPipeline()
+	pipe.HIncrBy(ctx, pendingKey, blogId.Hex(), 1)
+	pipe.ZIncrBy(ctx, bucket, 1, blogId.Hex())
+	pipe.Expire(ctx, bucket, s.retention)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetTrendingIds returns the blogs with the most views in the window, the
+// empty window name is the first configured window.
+func (s *service) GetTrendingIds(ctx context.Context, window string, p *coredto.Pagination) ([]primitive.ObjectID, error) {
+	w, err := s.findWindow(window)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s.store.GetInstance()
+	key := "trending_" + w.Name
+
+	exists, err := client.Exists(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		now := time.Now()
+		keys := make([]string, int(math.Ceil(w.Length.Hours())))
+		for i := range keys {
+			keys[i] = bucketKey(now.Add(-time.Duration(i) * time.Hour))
+		}
+
+		pipe := client.TxPipeline()
+		pipe.ZUnionStore(ctx, key, &goredis.ZStore{Keys: keys, Aggregate: "SUM"})
+		pipe.Expire(ctx, key, trendingTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	start := (p.Page - 1) * p.Limit
+	members, err := client.ZRevRange(ctx, key, start, start+p.Limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(members))
+	for _, m := range members {
+		if id, err := primitive.ObjectIDFromHex(m); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (s *service) findWindow(name string) (*Window, error) {
+	if name == "" {
+		return &s.config.Windows[0], nil
+	}
+
+	names := make([]string, len(s.config.Windows))
+	for i, w := range s.config.Windows {
+		if w.Name == name {
+			return &w, nil
+		}
+		names[i] = w.Name
+	}
+	return nil, network.NewBadRequestError("window must be one of "+strings.Join(names, ", "), nil)
+}
+
+// Flush adds the pending counts to the blogs. The counts that the database
+// has not taken are put back in redis, all of them when the write failed as a
+// whole, or else only those of the failed updates.
+func (s *service) Flush(ctx context.Context) error {
+	client := s.store.GetInstance()
+
+	res, err := takeScript.Run(ctx, client, []string{pendingKey}).StringSlice()
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(res)/2)
+	counts := make([]int64, 0, len(res)/2)
+	models := make([]mongod.WriteModel, 0, len(res)/2)
+	for i := 0; i+1 < len(res); i += 2 {
+		id, err := primitive.ObjectIDFromHex(res[i])
+		if err != nil {
+			continue
+		}
+		n, err := strconv.ParseInt(res[i+1], 10, 64)
+		if err != nil || n <= 0 {
+			continue
+		}
+		ids = append(ids, res[i])
+		counts = append(counts, n)
+		models = append(models, mongod.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$inc": bson.M{"viewCount": n}}))
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	opts := options.BulkWrite().SetOrdered(false)
+	_, err = s.blogQueryBuilder.GetCollection().BulkWrite(ctx, models, opts)
+	if err != nil {
+		failed := make([]int, len(models))
+		for i := range failed {
+			failed[i] = i
+		}
+		var bulkErr mongod.BulkWriteException
+		if errors.As(err, &bulkErr) {
+			failed = failed[:0]
+			for _, e := range bulkErr.WriteErrors {
+				failed = append(failed, e.Index)
+			}
+		}
+		s.restore(context.WithoutCancel(ctx), ids, counts, failed)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) restore(ctx context.Context, ids []string, counts []int64, failed []int) {
+	pipe := s.store.GetInstance().Pipeline()
+	for _, i := range failed {
+		pipe.HIncrBy(ctx, pendingKey, ids[i], counts[i])
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Error("view: counts lost after failed flush", "blogs", len(failed), "error", err)
+	}
+}
+
+// StartFlusher flushes the counts in the background until StopFlusher is
+// called.
+func (s *service) StartFlusher() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopFlusher = cancel
+	go s.flush(ctx)
+}
+
+// StopFlusher stops the background flush and flushes what is still pending.
+func (s *service) StopFlusher() {
+	if s.stopFlusher != nil {
+		s.stopFlusher()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Flush(ctx); err != nil {
+		s.logger.Error("view: final flush failed", "error", err)
+	}
+}
+
+func (s *service) flush(ctx context.Context) {
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Flush(ctx); err != nil {
+				s.logger.Error("view: flush failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package view
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Window is a trending window, views are kept in hourly buckets so the length
+// is rounded up to whole hours.
+type Window struct {
+	Name   string
+	Length time.Duration
+}
+
+// ParseWindows reads a comma separated list of windows in hours or days, as
+// in "24h,7d". The first window is the default one.
+func ParseWindows(value string) ([]Window, error) {
+	var windows []Window
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) < 2 {
+			continue
+		}
+
+		n, err := strconv.Atoi(name[:len(name)-1])
+		if err != nil || n <= 0 {
+			return nil, errors.New("invalid trending window " + name)
+		}
+
+		var unit time.Duration
+		switch name[len(name)-1] {
+		case 'h':
+			unit = time.Hour
+		case 'd':
+			unit = 24 * time.Hour
+		default:
+			return nil, errors.New("invalid trending window " + name + ", use h or d")
+		}
+
+		windows = append(windows, Window{Name: name, Length: time.Duration(n) * unit})
+	}
+
+	if len(windows) == 0 {
+		return nil, errors.New("no trending window is configured")
+	}
+	return windows, nil
+}
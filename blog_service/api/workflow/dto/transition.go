@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/gomicro/blog-service/api/workflow/model"
+	"github.com/google/uuid"
+)
+
+type Transition struct {
+	Action    model.Action     `json:"action"`
+	From      blogModel.State  `json:"from"`
+	To        blogModel.State  `json:"to"`
+	Role      message.RoleCode `json:"role"`
+	CreatedBy uuid.UUID        `json:"createdBy"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+func NewTransition(transition *model.Transition) *Transition {
+	return &Transition{
+		Action:    transition.Action,
+		From:      transition.From,
+		To:        transition.To,
+		Role:      transition.Role,
+		CreatedBy: transition.CreatedBy,
+		CreatedAt: transition.CreatedAt,
+	}
+}
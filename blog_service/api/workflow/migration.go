@@ -0,0 +1,58 @@
+package workflow
+
+import (
+	"context"
+
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"go.mongodb.org/mongo-driver/bson"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+)
+
+// indexes on the flags that the state has replaced
+var legacyIndexNames = []string{
+	"_id_1_status_1",
+	"published_1_status_1",
+	"_id_1_published_1_status_1",
+	"slug_1_published_1_status_1",
+	"tags_1_published_1_status_1",
+}
+
+// Migrate converts the blogs still on the drafted, submitted, published and
+// status flags into a state and removes the flags, and gives the blogs without
+// a version the first one. It can be run again, the blogs that have a state
+// and a version are left alone. The transition log of a migrated blog starts
+// with its next move.
+func (s *service) Migrate(ctx context.Context) (int64, error) {
+	filter := bson.M{"state": bson.M{"$exists": false}}
+	state := bson.M{"$switch": bson.M{
+		"branches": bson.A{
+			bson.M{"case": bson.M{"$ne": bson.A{"$status", true}}, "then": blogModel.StateDeleted},
+			bson.M{"case": bson.M{"$eq": bson.A{"$published", true}}, "then": blogModel.StatePublished},
+			bson.M{"case": bson.M{"$eq": bson.A{"$submitted", true}}, "then": blogModel.StateSubmitted},
+		},
+		"default": blogModel.StateDraft,
+	}}
+	update := mongod.Pipeline{
+		{{Key: "$set", Value: bson.M{"state": state}}},
+		{{Key: "$unset", Value: bson.A{"drafted", "submitted", "published", "status"}}},
+	}
+
+	collection := s.blogQueryBuilder.GetCollection()
+	result, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	filter = bson.M{"version": bson.M{"$exists": false}}
+	versioned, err := collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"version": 1}})
+	if err != nil {
+		return result.ModifiedCount, err
+	}
+
+	for _, name := range legacyIndexNames {
+		// a database created after the change never had them
+		collection.Indexes().DropOne(ctx, name)
+	}
+
+	return max(result.ModifiedCount, versioned.ModifiedCount), nil
+}
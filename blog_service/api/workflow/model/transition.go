@@ -0,0 +1,72 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+)
+
+const CollectionName = "blog_transitions"
+
+type Action string
+
+const (
+	ActionSubmit    Action = "SUBMIT"
+	ActionWithdraw  Action = "WITHDRAW"
+	ActionSchedule  Action = "SCHEDULE"
+	ActionCancel    Action = "CANCEL"
+	ActionPublish   Action = "PUBLISH"
+	ActionUnpublish Action = "UNPUBLISH"
+	ActionReject    Action = "REJECT"
+	ActionDelete    Action = "DELETE"
+)
+
+// Transition is an entry of the append-only log of the state changes of a
+// blog, it is never updated or removed.
+type Transition struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	BlogID    primitive.ObjectID `bson:"blogId" validate:"required"`
+	Action    Action             `bson:"action" validate:"required"`
+	From      blogModel.State    `bson:"from" validate:"required"`
+	To        blogModel.State    `bson:"to" validate:"required"`
+	Role      message.RoleCode   `bson:"role" validate:"required"`
+	CreatedBy uuid.UUID          `bson:"createdBy" validate:"required"`
+	CreatedAt time.Time          `bson:"createdAt" validate:"required"`
+}
+
+func NewTransition(blogId primitive.ObjectID, action Action, from, to blogModel.State, role message.RoleCode, actor uuid.UUID) (*Transition, error) {
+	t := Transition{
+		BlogID:    blogId,
+		Action:    action,
+		From:      from,
+		To:        to,
+		Role:      role,
+		CreatedBy: actor,
+		CreatedAt: time.Now(),
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (transition *Transition) Validate() error {
+	validate := validator.New()
+	return validate.Struct(transition)
+}
+
+func (*Transition) EnsureIndexes(db mongo.Database) {
+	indexes := []mongod.IndexModel{
+		{Keys: bson.D{{Key: "blogId", Value: 1}, {Key: "createdAt", Value: 1}}},
+	}
+
+	mongo.NewQueryBuilder[Transition](db, CollectionName).Query(context.Background()).CreateIndexes(indexes)
+}
@@ -0,0 +1,150 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	blogDto "github.com/afteracademy/gomicro/blog-service/api/blog/dto"
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	eventMsg "github.com/afteracademy/gomicro/blog-service/api/event/message"
+	"github.com/afteracademy/gomicro/blog-service/api/workflow/dto"
+	"github.com/afteracademy/gomicro/blog-service/api/workflow/model"
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongod "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Service interface {
+	Move(ctx context.Context, blogId primitive.ObjectID, action model.Action, user *message.User, role message.RoleCode, filter bson.M, set bson.M, event *eventMsg.BlogEvent) (*blogModel.Blog, error)
+	GetHistory(ctx context.Context, blogId primitive.ObjectID) ([]*dto.Transition, error)
+	Migrate(ctx context.Context) (int64, error)
+}
+
+type service struct {
+	blogQueryBuilder       mongo.QueryBuilder[blogModel.Blog]
+	transitionQueryBuilder mongo.QueryBuilder[model.Transition]
+}
+
+func NewService(db mongo.Database) Service {
+	return &service{
+		blogQueryBuilder:       mongo.NewQueryBuilder[blogModel.Blog](db, blogModel.CollectionName),
+		transitionQueryBuilder: mongo.NewQueryBuilder[model.Transition](db, model.CollectionName),
+	}
+}
+
+// Move applies the action to the blog matching the filter, if the table allows
+// it for the role and the current state. The state is checked by the update
+// itself, so that of two concurrent moves only one can succeed. The fields of
+// set are written along, as an aggregation stage, so that they can refer to
+// the other fields of the blog. A version in the filter is checked like the
+// state, and a mismatch is reported as a conflict. It returns the blog as it
+// was before the move. The event, if any, is stored on the blog by the same
+// update, and gets the slug of the blog.
+func (s *service) Move(ctx context.Context, blogId primitive.ObjectID, action model.Action, user *message.User, role message.RoleCode, filter bson.M, set bson.M, event *eventMsg.BlogEvent) (*blogModel.Blog, error) {
+	m, ok := moves[action]
+	if !ok {
+		return nil, network.NewBadRequestError("unknown action "+string(action), nil)
+	}
+	if !m.allows(role) {
+		return nil, network.NewForbiddenError(string(role)+" can not "+strings.ToLower(string(action))+" a blog", nil)
+	}
+
+	match := bson.M{"_id": blogId}
+	for k, v := range filter {
+		match[k] = v
+	}
+	match["state"] = bson.M{"$in": m.from}
+
+	fields := bson.M{
+		"state":     m.to,
+		"version":   bson.M{"$add": bson.A{"$version", 1}},
+		"updatedBy": user.ID,
+		"updatedAt": time.Now(),
+	}
+	for k, v := range set {
+		fields[k] = v
+	}
+	if event != nil {
+		pending, err := eventMsg.NewPendingExpression(event)
+		if err != nil {
+			return nil, err
+		}
+		fields["pendingEvents"] = bson.M{"$concatArrays": bson.A{
+			bson.M{"$ifNull": bson.A{"$pendingEvents", bson.A{}}},
+			bson.A{pending},
+		}}
+	}
+	update := mongod.Pipeline{{{Key: "$set", Value: fields}}}
+
+	var blog blogModel.Blog
+	err := s.blogQueryBuilder.GetCollection().FindOneAndUpdate(ctx, match, update).Decode(&blog)
+	if err == mongod.ErrNoDocuments {
+		return nil, s.refusal(ctx, blogId, action, m, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if event != nil {
+		event.Slug = blog.Slug
+	}
+
+	transition, err := model.NewTransition(blog.ID, action, blog.State, m.to, role, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the move has happened, the log entry must not be lost with the request
+	_, err = s.transitionQueryBuilder.Query(context.WithoutCancel(ctx)).InsertOne(transition)
+	if err != nil {
+		return nil, err
+	}
+
+	return &blog, nil
+}
+
+// refusal tells a missing blog apart from one in a state the action does not
+// start from.
+func (s *service) refusal(ctx context.Context, blogId primitive.ObjectID, action model.Action, m move, filter bson.M) error {
+	match := bson.M{"_id": blogId, "state": bson.M{"$ne": blogModel.StateDeleted}}
+	for k, v := range filter {
+		if k != "version" {
+			match[k] = v
+		}
+	}
+	opts := options.FindOne().SetProjection(bson.D{{Key: "state", Value: 1}, {Key: "version", Value: 1}})
+
+	blog, err := s.blogQueryBuilder.Query(ctx).FindOne(match, opts)
+	if err != nil {
+		return network.NewNotFoundError("blog for _id "+blogId.Hex()+" not found", err)
+	}
+	if version, ok := filter["version"]; ok && version != blog.Version {
+		return blogDto.NewVersionConflictError(blog.Version)
+	}
+	if !m.startsFrom(blog.State) {
+		return network.NewBadRequestError("blog for _id "+blogId.Hex()+" is "+string(blog.State)+", "+strings.ToLower(string(action))+" is not allowed", nil)
+	}
+	// the state has changed between the update and this lookup
+	return network.NewBadRequestError("blog for _id "+blogId.Hex()+" was changed concurrently, try again", nil)
+}
+
+func (s *service) GetHistory(ctx context.Context, blogId primitive.ObjectID) ([]*dto.Transition, error) {
+	filter := bson.M{"blogId": blogId}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	transitions, err := s.transitionQueryBuilder.Query(ctx).FindAll(filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*dto.Transition, len(transitions))
+	for i, t := range transitions {
+		dtos[i] = dto.NewTransition(t)
+	}
+
+	return dtos, nil
+}
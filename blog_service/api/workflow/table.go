@@ -0,0 +1,68 @@
+package workflow
+
+import (
+	"slices"
+
+	"github.com/afteracademy/gomicro/blog-service/api/auth/message"
+	blogModel "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	"github.com/afteracademy/gomicro/blog-service/api/workflow/model"
+)
+
+type move struct {
+	from  []blogModel.State
+	to    blogModel.State
+	roles []message.RoleCode
+}
+
+// moves is the transition table of the blog workflow, any other change of
+// state is refused.
+var moves = map[model.Action]move{
+	model.ActionSubmit: {
+		from:  []blogModel.State{blogModel.StateDraft},
+		to:    blogModel.StateSubmitted,
+		roles: []message.RoleCode{message.RoleCodeAuthor},
+	},
+	model.ActionWithdraw: {
+		from:  []blogModel.State{blogModel.StateSubmitted, blogModel.StateScheduled},
+		to:    blogModel.StateDraft,
+		roles: []message.RoleCode{message.RoleCodeAuthor},
+	},
+	model.ActionSchedule: {
+		from:  []blogModel.State{blogModel.StateSubmitted},
+		to:    blogModel.StateScheduled,
+		roles: []message.RoleCode{message.RoleCodeEditor},
+	},
+	model.ActionCancel: {
+		from:  []blogModel.State{blogModel.StateScheduled},
+		to:    blogModel.StateSubmitted,
+		roles: []message.RoleCode{message.RoleCodeEditor},
+	},
+	model.ActionPublish: {
+		from:  []blogModel.State{blogModel.StateSubmitted, blogModel.StateScheduled},
+		to:    blogModel.StatePublished,
+		roles: []message.RoleCode{message.RoleCodeEditor},
+	},
+	model.ActionUnpublish: {
+		from:  []blogModel.State{blogModel.StatePublished},
+		to:    blogModel.StateDraft,
+		roles: []message.RoleCode{message.RoleCodeEditor},
+	},
+	model.ActionReject: {
+		from:  []blogModel.State{blogModel.StateSubmitted},
+		to:    blogModel.StateDraft,
+		roles: []message.RoleCode{message.RoleCodeEditor},
+	},
+	model.ActionDelete: {
+		from:  []blogModel.State{blogModel.StateDraft, blogModel.StateSubmitted, blogModel.StateScheduled, blogModel.StatePublished},
+		to:    blogModel.StateDeleted,
+		roles: []message.RoleCode{message.RoleCodeAuthor},
+	},
+}
+
+func (m move) allows(role message.RoleCode) bool {
+	return slices.Contains(m.roles, role)
+}
+
+func (m move) startsFrom(state blogModel.State) bool {
+	return slices.Contains(m.from, state)
+}
@@ -0,0 +1,7 @@
+package main
+
+import "github.com/afteracademy/gomicro/blog-service/startup"
+
+func main() {
+	startup.RecountTags()
+}
@@ -0,0 +1,88 @@
+package common
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+type BreakerState struct {
+	Subject  string `json:"subject"`
+	State    string `json:"state"`
+	Failures int    `json:"failures"`
+}
+
+// breaker opens after threshold consecutive failures and rejects calls until
+// the cooldown has passed. It then lets a single probe through: a success
+// closes it again, a failure re-opens it for another cooldown.
+type breaker struct {
+	mu        sync.Mutex
+	state     string
+	failures  int
+	openedAt  time.Time
+	threshold int
+	cooldown  time.Duration
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{
+		state:     BreakerClosed,
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		return true
+	case BreakerHalfOpen:
+		// a probe is already in flight
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = BreakerClosed
+	b.failures = 0
+}
+
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *breaker) snapshot(subject string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state := b.state
+	if state == BreakerOpen && time.Since(b.openedAt) >= b.cooldown {
+		state = BreakerHalfOpen
+	}
+	return BreakerState{
+		Subject:  subject,
+		State:    state,
+		Failures: b.failures,
+	}
+}
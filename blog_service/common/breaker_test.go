@@ -0,0 +1,58 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreakerStates(t *testing.T) {
+	b := newBreaker(2, 20*time.Millisecond)
+
+	b.failure()
+	if got := b.snapshot("s").State; got != BreakerClosed {
+		t.Fatalf("after one failure state = %s, want closed", got)
+	}
+	if !b.allow() {
+		t.Fatal("closed breaker rejected a call")
+	}
+
+	b.failure()
+	if got := b.snapshot("s").State; got != BreakerOpen {
+		t.Fatalf("after the threshold state = %s, want open", got)
+	}
+	if b.allow() {
+		t.Fatal("open breaker let a call through within the cooldown")
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("breaker let no probe through after the cooldown")
+	}
+	if b.allow() {
+		t.Fatal("half-open breaker let a second probe through")
+	}
+
+	b.failure()
+	if got := b.snapshot("s").State; got != BreakerOpen {
+		t.Fatalf("after a failed probe state = %s, want open", got)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	b.allow()
+	b.success()
+	state := b.snapshot("s")
+	if state.State != BreakerClosed || state.Failures != 0 {
+		t.Fatalf("after a successful probe got %+v, want closed without failures", state)
+	}
+}
+
+func TestBreakerResetsOnSuccess(t *testing.T) {
+	b := newBreaker(2, time.Minute)
+
+	b.failure()
+	b.success()
+	b.failure()
+	if got := b.snapshot("s").State; got != BreakerClosed {
+		t.Fatalf("failures apart from each other opened the breaker: %s", got)
+	}
+}
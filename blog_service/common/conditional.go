@@ -0,0 +1,37 @@
+package common
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotModified sends the validators of the representation and tells if the
+// client already holds it, by If-None-Match, or else by If-Modified-Since.
+func NotModified(ctx *gin.Context, etag string, lastModified time.Time) bool {
+	ctx.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		ctx.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if match := ctx.GetHeader("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := ctx.GetHeader("If-Modified-Since"); since != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(since)
+		// the header only has seconds
+		if err == nil && !lastModified.Truncate(time.Second).After(t) {
+			return true
+		}
+	}
+	return false
+}
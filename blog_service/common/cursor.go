@@ -0,0 +1,152 @@
+package common
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+
+	"github.com/afteracademy/goserve/v2/mongo"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CursorOrder is the order a cursor walks in, the fields all descending. The
+// last field must keep the order total, e.g. _id.
+type CursorOrder []string
+
+// UpdatedOrder is the latest updated first, the score breaking the ties.
+var UpdatedOrder = CursorOrder{"updatedAt", "score", "_id"}
+
+// PublishedOrder is the latest published first.
+var PublishedOrder = CursorOrder{"publishedAt", "_id"}
+
+func (o CursorOrder) Sort() bson.D {
+	sort := make(bson.D, len(o))
+	for i, field := range o {
+		sort[i] = bson.E{Key: field, Value: -1}
+	}
+	return sort
+}
+
+// Cursor is the position after the last document of a page, the values of the
+// fields of its order, handed to the client as an opaque string.
+type Cursor struct {
+	order  CursorOrder
+	values []bson.RawValue
+}
+
+// NewCursor takes the position of the document in the order, a field that the
+// document does not have is taken as null.
+func NewCursor(order CursorOrder, document any) (*Cursor, error) {
+	raw, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cursor{order: order, values: make([]bson.RawValue, len(order))}
+	for i, field := range order {
+		value, err := bson.Raw(raw).LookupErr(field)
+		if err != nil {
+			value = bson.RawValue{Type: bsontype.Null}
+		}
+		c.values[i] = value
+	}
+	return c, nil
+}
+
+// DecodeCursor reads a cursor of the order. The client may have changed it, so
+// only a value that a field of the order can hold is taken, never a document
+// that the query would read as an operator.
+func DecodeCursor(order CursorOrder, value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, network.NewBadRequestError("cursor is invalid", err)
+	}
+	raw := bson.Raw(data)
+	if err = raw.Validate(); err != nil {
+		return nil, network.NewBadRequestError("cursor is invalid", err)
+	}
+
+	c := &Cursor{order: order, values: make([]bson.RawValue, len(order))}
+	for i, field := range order {
+		v, err := raw.LookupErr(field)
+		if err != nil {
+			return nil, network.NewBadRequestError("cursor is invalid", err)
+		}
+		switch v.Type {
+		case bsontype.DateTime, bsontype.Double, bsontype.Int32, bsontype.Int64,
+			bsontype.ObjectID, bsontype.String, bsontype.Null:
+			c.values[i] = v
+		default:
+			return nil, network.NewBadRequestError("cursor is invalid", errors.New(field+" has type "+v.Type.String()))
+		}
+	}
+	return c, nil
+}
+
+func (c *Cursor) Encode() string {
+	doc := make(bson.D, len(c.order))
+	for i, field := range c.order {
+		doc[i] = bson.E{Key: field, Value: c.values[i]}
+	}
+	data, _ := bson.Marshal(doc)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// After restricts the filter to the documents that come after the cursor in
+// its order.
+func (c *Cursor) After(filter bson.M) bson.M {
+	after := make(bson.A, len(c.order))
+	for i, field := range c.order {
+		clause := bson.M{field: bson.M{"$lt": c.values[i]}}
+		for j := range i {
+			clause[c.order[j]] = c.values[j]
+		}
+		after[i] = clause
+	}
+	return bson.M{"$and": bson.A{filter, bson.M{"$or": after}}}
+}
+
+// FindByCursor reads the page of up to limit documents that come after the
+// cursor in the order, an empty cursor reading the first page. It returns the
+// cursor of the page after it, or nil on the last page.
+func FindByCursor[T any](ctx context.Context, builder mongo.QueryBuilder[T], order CursorOrder, filter bson.M, cursor string, limit int64, opts *options.FindOptions) ([]*T, *string, error) {
+	if cursor != "" {
+		c, err := DecodeCursor(order, cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		filter = c.After(filter)
+	}
+
+	if opts == nil {
+		opts = options.Find()
+	}
+	opts.SetSort(order.Sort()).SetLimit(limit + 1)
+
+	docs, err := builder.Query(ctx).FindAll(filter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if int64(len(docs)) <= limit {
+		return docs, nil, nil
+	}
+
+	docs = docs[:limit]
+	c, err := NewCursor(order, docs[len(docs)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	next := c.Encode()
+	return docs, &next, nil
+}
+
+// HasCursor tells if the request asks for cursor pagination, an empty cursor
+// asking for the first page, rather than for page and limit.
+func HasCursor(ctx *gin.Context) bool {
+	_, ok := ctx.GetQuery("cursor")
+	return ok
+}
@@ -0,0 +1,102 @@
+package common
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type cursorDoc struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+	Score     float64            `bson:"score"`
+}
+
+// roundTrip gives the filter as the database reads it, e.g. a raw value as the
+// value it holds.
+func roundTrip(t *testing.T, filter bson.M) bson.M {
+	t.Helper()
+
+	data, err := bson.Marshal(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m bson.M
+	if err = bson.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	doc := cursorDoc{ID: primitive.NewObjectID(), UpdatedAt: time.UnixMilli(1700000000000), Score: 0.5}
+
+	c, err := NewCursor(UpdatedOrder, &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeCursor(UpdatedOrder, c.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updatedAt := primitive.NewDateTimeFromTime(doc.UpdatedAt)
+	want := bson.M{"$and": bson.A{
+		bson.M{"state": "PUBLISHED"},
+		bson.M{"$or": bson.A{
+			bson.M{"updatedAt": bson.M{"$lt": updatedAt}},
+			bson.M{"updatedAt": updatedAt, "score": bson.M{"$lt": doc.Score}},
+			bson.M{"updatedAt": updatedAt, "score": doc.Score, "_id": bson.M{"$lt": doc.ID}},
+		}},
+	}}
+	got := roundTrip(t, decoded.After(bson.M{"state": "PUBLISHED"}))
+	if !reflect.DeepEqual(got, roundTrip(t, want)) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestCursorMissingField(t *testing.T) {
+	doc := cursorDoc{ID: primitive.NewObjectID()}
+
+	c, err := NewCursor(PublishedOrder, &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = DecodeCursor(PublishedOrder, c.Encode()); err != nil {
+		t.Fatalf("cursor with a null field rejected: %v", err)
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	encode := func(doc bson.D) string {
+		data, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "not base64", value: "%%%"},
+		{name: "not bson", value: base64.RawURLEncoding.EncodeToString([]byte("{}"))},
+		{name: "other order", value: encode(bson.D{{Key: "updatedAt", Value: time.Now()}, {Key: "_id", Value: id}})},
+		{name: "operator", value: encode(bson.D{{Key: "publishedAt", Value: bson.M{"$ne": nil}}, {Key: "_id", Value: id}})},
+		{name: "array", value: encode(bson.D{{Key: "publishedAt", Value: bson.A{1}}, {Key: "_id", Value: id}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(PublishedOrder, tt.value); err == nil {
+				t.Fatal("cursor accepted")
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package common
+
+import (
+	"context"
+	"time"
+
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+)
+
+type requestDeadline struct {
+	timeout time.Duration
+}
+
+// NewRequestDeadline bounds the request context, which is handed down to the
+// database and NATS calls, so that work is cancelled when the client
+// disconnects or the timeout passes.
+func NewRequestDeadline(timeout time.Duration) network.RootMiddleware {
+	return &requestDeadline{
+		timeout: timeout,
+	}
+}
+
+func (m *requestDeadline) Attach(engine *gin.Engine) {
+	engine.Use(m.Handler)
+}
+
+func (m *requestDeadline) Handler(ctx *gin.Context) {
+	if m.timeout <= 0 {
+		ctx.Next()
+		return
+	}
+	c, cancel := context.WithTimeout(ctx.Request.Context(), m.timeout)
+	defer cancel()
+	ctx.Request = ctx.Request.WithContext(c)
+	ctx.Next()
+}
@@ -0,0 +1,96 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+)
+
+const failureCode network.ResCode = "10001"
+
+// apiError covers the status codes that goserve's network package does not
+// construct on its own.
+type apiError struct {
+	Code    int
+	Message string
+	Err     error
+	Data    any
+}
+
+func (e *apiError) GetCode() int {
+	return e.Code
+}
+
+func (e *apiError) GetMessage() string {
+	return e.Message
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%d - %s: %v", e.Code, e.Message, e.Err)
+}
+
+func (e *apiError) Unwrap() error {
+	return e.Err
+}
+
+func newApiError(code int, message string, err error) network.ApiError {
+	if err == nil {
+		err = errors.New(message)
+	}
+	return &apiError{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+func NewServiceUnavailableError(message string, err error) network.ApiError {
+	return newApiError(http.StatusServiceUnavailable, message, err)
+}
+
+func NewPreconditionRequiredError(message string, err error) network.ApiError {
+	return newApiError(http.StatusPreconditionRequired, message, err)
+}
+
+// NewConflictError carries data, e.g. the current version, so that the client
+// can recover without another request.
+func NewConflictError(message string, data any) network.ApiError {
+	e := newApiError(http.StatusConflict, message, nil).(*apiError)
+	e.Data = data
+	return e
+}
+
+func IsServiceUnavailable(err error) bool {
+	var apiError network.ApiError
+	return errors.As(err, &apiError) && apiError.GetCode() == http.StatusServiceUnavailable
+}
+
+func IsNotFound(err error) bool {
+	var apiError network.ApiError
+	return errors.As(err, &apiError) && apiError.GetCode() == http.StatusNotFound
+}
+
+// IsClientError tells the errors that come from the request itself, and would
+// happen again if it were repeated.
+func IsClientError(err error) bool {
+	var apiError network.ApiError
+	return errors.As(err, &apiError) && apiError.GetCode() >= 400 && apiError.GetCode() < 500
+}
+
+// SendMixedError works like network.SendMixedError, and in addition sends the
+// status codes created in this package instead of collapsing them into 500.
+func SendMixedError(ctx *gin.Context, err error) {
+	var e *apiError
+	if errors.As(err, &e) {
+		var data *any
+		if e.Data != nil {
+			data = &e.Data
+		}
+		network.SendCustomResponse(ctx, failureCode, e.GetCode(), e.GetMessage(), data)
+		return
+	}
+	network.SendMixedError(ctx, err)
+}
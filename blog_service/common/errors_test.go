@@ -0,0 +1,34 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSendMixedErrorServiceUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	err := fmt.Errorf("auth lookup: %w", NewServiceUnavailableError("auth.authentication is unavailable, please retry later", ErrCircuitOpen))
+	if !IsServiceUnavailable(err) {
+		t.Fatal("wrapped 503 not recognized")
+	}
+	if IsClientError(err) {
+		t.Fatal("503 taken for a client error")
+	}
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("cause lost")
+	}
+
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	SendMixedError(ctx, err)
+
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", recorder.Code)
+	}
+}
@@ -0,0 +1,16 @@
+package common
+
+import (
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FollowUp logs the failure of a step that follows a stored change of a blog.
+// The change stands without the step, e.g. a cache that was not dropped still
+// expires, and an index that missed the change is repaired by its rebuild.
+func FollowUp(logger *slog.Logger, step string, blogId primitive.ObjectID, err error) {
+	if err != nil {
+		logger.Error(step+" failed", "blog", blogId.Hex(), "error", err)
+	}
+}
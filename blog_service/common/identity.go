@@ -0,0 +1,60 @@
+package common
+
+import (
+	"time"
+
+	"github.com/afteracademy/gomicro/contracts/identity"
+	"github.com/nats-io/nats.go"
+)
+
+// broadcastTTL is how old a signed broadcast between the instances may be
+const broadcastTTL = 30 * time.Second
+
+type ServiceIdentity interface {
+	Sign(msg *nats.Msg)
+	Verify(msg *nats.Msg) error
+}
+
+type serviceIdentity struct {
+	service string
+	secret  []byte
+}
+
+func NewServiceIdentity(service string, secret string) ServiceIdentity {
+	return &serviceIdentity{
+		service: service,
+		secret:  []byte(secret),
+	}
+}
+
+func (s *serviceIdentity) Sign(msg *nats.Msg) {
+	if msg.Header == nil {
+		msg.Header = nats.Header{}
+	}
+	headers := identity.NewHeaders(s.secret, s.service, msg.Subject, msg.Data, time.Now())
+	for key, value := range headers {
+		msg.Header.Set(key, value)
+	}
+}
+
+// Verify accepts a message signed by an instance of this service, which share
+// the name and the secret. It is meant for the broadcasts between them. A
+// broadcast reaches every instance, so its nonce is not remembered, a replay
+// within the TTL only drops a cached copy once more.
+func (s *serviceIdentity) Verify(msg *nats.Msg) error {
+	service := msg.Header.Get(identity.HeaderService)
+	if service != s.service {
+		return identity.ErrMissingIdentity
+	}
+	return identity.Verify(
+		s.secret,
+		service,
+		msg.Subject,
+		msg.Header.Get(identity.HeaderTimestamp),
+		msg.Header.Get(identity.HeaderNonce),
+		msg.Header.Get(identity.HeaderSignature),
+		msg.Data,
+		broadcastTTL,
+		time.Now(),
+	)
+}
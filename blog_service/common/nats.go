@@ -0,0 +1,201 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/afteracademy/gomicro/contracts/deadline"
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/nats-io/nats.go"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// NatsPolicy controls how requests on a subject are made. Retries are only
+// attempted for idempotent subjects and only on transport failures, i.e. when
+// no reply was received at all.
+type NatsPolicy struct {
+	Timeout    time.Duration
+	Retries    int
+	Idempotent bool
+}
+
+// defaults of the NatsCallerConfig fields that are not set
+const (
+	DefaultRetryBackoff     = 100 * time.Millisecond
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+type NatsCallerConfig struct {
+	Timeout          time.Duration
+	RetryBackoff     time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+type NatsCaller interface {
+	SetPolicy(subject string, policy NatsPolicy)
+	BreakerStates() []BreakerState
+	request(ctx context.Context, subject string, data []byte) (*nats.Msg, error)
+}
+
+type natsCaller struct {
+	client   micro.NatsClient
+	identity ServiceIdentity
+	config   NatsCallerConfig
+	mu       sync.RWMutex
+	policies map[string]NatsPolicy
+	breakers map[string]*breaker
+}
+
+func NewNatsCaller(client micro.NatsClient, identity ServiceIdentity, config NatsCallerConfig) NatsCaller {
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = DefaultRetryBackoff
+	}
+	if config.BreakerThreshold <= 0 {
+		config.BreakerThreshold = DefaultBreakerThreshold
+	}
+	if config.BreakerCooldown <= 0 {
+		config.BreakerCooldown = DefaultBreakerCooldown
+	}
+
+	return &natsCaller{
+		client:   client,
+		identity: identity,
+		config:   config,
+		policies: make(map[string]NatsPolicy),
+		breakers: make(map[string]*breaker),
+	}
+}
+
+func (c *natsCaller) SetPolicy(subject string, policy NatsPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.policies[subject] = policy
+}
+
+func (c *natsCaller) BreakerStates() []BreakerState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	states := make([]BreakerState, 0, len(c.breakers))
+	for subject, b := range c.breakers {
+		states = append(states, b.snapshot(subject))
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Subject < states[j].Subject })
+	return states
+}
+
+func (c *natsCaller) policy(subject string) (NatsPolicy, *breaker) {
+	c.mu.RLock()
+	policy, ok := c.policies[subject]
+	b := c.breakers[subject]
+	c.mu.RUnlock()
+
+	if !ok {
+		policy = NatsPolicy{Timeout: c.config.Timeout}
+	}
+
+	if b == nil {
+		c.mu.Lock()
+		if b = c.breakers[subject]; b == nil {
+			b = newBreaker(c.config.BreakerThreshold, c.config.BreakerCooldown)
+			c.breakers[subject] = b
+		}
+		c.mu.Unlock()
+	}
+
+	return policy, b
+}
+
+func (c *natsCaller) request(ctx context.Context, subject string, data []byte) (*nats.Msg, error) {
+	policy, b := c.policy(subject)
+
+	attempts := 1
+	if policy.Idempotent {
+		attempts += policy.Retries
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(c.backoff(attempt)):
+			}
+		}
+
+		if !b.allow() {
+			return nil, NewServiceUnavailableError(subject+" is unavailable, please retry later", ErrCircuitOpen)
+		}
+
+		var reply *nats.Msg
+		reply, err = c.attempt(ctx, subject, data, policy.Timeout)
+		if err == nil {
+			b.success()
+			return reply, nil
+		}
+
+		// the caller gave up, which says nothing about the remote service
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		b.failure()
+	}
+
+	return nil, NewServiceUnavailableError(subject+" did not respond", err)
+}
+
+// attempt sends a single request bounded by both the caller context and the
+// subject timeout. The resulting deadline is sent along so that the remote
+// service can stop working on it once nobody waits for the reply.
+func (c *natsCaller) attempt(ctx context.Context, subject string, data []byte, timeout time.Duration) (*nats.Msg, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	if value, ok := deadline.Format(ctx); ok {
+		msg.Header.Set(deadline.HeaderDeadline, value)
+	}
+	c.identity.Sign(msg)
+
+	return c.client.GetInstance().Conn.RequestMsgWithContext(ctx, msg)
+}
+
+// backoff grows exponentially with jitter, so that retries from many
+// instances do not hit the remote service in lockstep.
+func (c *natsCaller) backoff(attempt int) time.Duration {
+	base := c.config.RetryBackoff << (attempt - 1)
+	if base <= 0 {
+		return 0
+	}
+	return base/2 + rand.N(base/2+1)
+}
+
+// RequestNats works like micro.RequestNats but goes through the caller, which
+// signs the request with the identity of this service and applies the subject
+// policy and circuit breaker.
+func RequestNats[S any, R any](ctx context.Context, caller NatsCaller, subject string, sData *S) (*R, error) {
+	msgJson, err := micro.MsgToJson(sData)
+	if err != nil {
+		return nil, err
+	}
+
+	natsMsg, err := caller.request(ctx, subject, msgJson)
+	if err != nil {
+		return nil, err
+	}
+
+	return micro.JsonToMsg[R](natsMsg.Data)
+}
@@ -0,0 +1,183 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/afteracademy/goserve/v2/micro"
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+const testSubject = "auth.authentication"
+
+func startNats(t *testing.T) micro.NatsClient {
+	t.Helper()
+
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	t.Cleanup(ns.Shutdown)
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	client := micro.NewNatsClient(&micro.Config{
+		NatsUrl:            ns.ClientURL(),
+		NatsServiceName:    "blog",
+		NatsServiceVersion: "1.0.0",
+		Timeout:            time.Second,
+	})
+	t.Cleanup(client.Disconnect)
+	return client
+}
+
+// subscribe counts the requests on the subject and replies to them when
+// reply is set, the others time out.
+func subscribe(t *testing.T, client micro.NatsClient, reply bool) *atomic.Int32 {
+	t.Helper()
+
+	var count atomic.Int32
+	sub, err := client.GetInstance().Conn.Subscribe(testSubject, func(msg *nats.Msg) {
+		count.Add(1)
+		if reply {
+			msg.Respond([]byte(`{"data":{"value":"ok"}}`))
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sub.Unsubscribe() })
+	client.GetInstance().Conn.Flush()
+	return &count
+}
+
+func newTestCaller(client micro.NatsClient, policy NatsPolicy, threshold int) NatsCaller {
+	caller := NewNatsCaller(client, NewServiceIdentity("blog", "changeit"), NatsCallerConfig{
+		RetryBackoff:     time.Millisecond,
+		BreakerThreshold: threshold,
+		BreakerCooldown:  time.Minute,
+	})
+	caller.SetPolicy(testSubject, policy)
+	return caller
+}
+
+func assertUnavailable(t *testing.T, err error) {
+	t.Helper()
+
+	var apiErr network.ApiError
+	if !errors.As(err, &apiErr) || apiErr.GetCode() != http.StatusServiceUnavailable {
+		t.Fatalf("got %v, want a 503", err)
+	}
+}
+
+func TestNatsCallerRetries(t *testing.T) {
+	client := startNats(t)
+	count := subscribe(t, client, false)
+
+	tests := []struct {
+		name   string
+		policy NatsPolicy
+		sent   int32
+	}{
+		{name: "idempotent", policy: NatsPolicy{Timeout: 20 * time.Millisecond, Retries: 2, Idempotent: true}, sent: 3},
+		{name: "not idempotent", policy: NatsPolicy{Timeout: 20 * time.Millisecond, Retries: 2}, sent: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count.Store(0)
+			caller := newTestCaller(client, tt.policy, 10)
+
+			_, err := caller.request(context.Background(), testSubject, []byte(`{}`))
+			assertUnavailable(t, err)
+			if got := count.Load(); got != tt.sent {
+				t.Fatalf("sent %d requests, want %d", got, tt.sent)
+			}
+		})
+	}
+}
+
+func TestNatsCallerOpensBreaker(t *testing.T) {
+	client := startNats(t)
+	count := subscribe(t, client, false)
+	caller := newTestCaller(client, NatsPolicy{Timeout: 20 * time.Millisecond}, 2)
+
+	for range 2 {
+		_, err := caller.request(context.Background(), testSubject, []byte(`{}`))
+		assertUnavailable(t, err)
+	}
+
+	_, err := caller.request(context.Background(), testSubject, []byte(`{}`))
+	assertUnavailable(t, err)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got %v, want the open circuit", err)
+	}
+	if got := count.Load(); got != 2 {
+		t.Fatalf("sent %d requests, want 2 before the breaker opened", got)
+	}
+
+	states := caller.BreakerStates()
+	if len(states) != 1 || states[0].State != BreakerOpen {
+		t.Fatalf("got %+v, want the subject open", states)
+	}
+}
+
+func TestNatsCallerSignsRequests(t *testing.T) {
+	client := startNats(t)
+	identity := NewServiceIdentity("blog", "changeit")
+
+	var verified atomic.Bool
+	sub, err := client.GetInstance().Conn.Subscribe(testSubject, func(msg *nats.Msg) {
+		verified.Store(identity.Verify(msg) == nil)
+		msg.Respond([]byte(`{"data":{"value":"ok"}}`))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	caller := newTestCaller(client, NatsPolicy{Timeout: time.Second}, 10)
+	if _, err = caller.request(context.Background(), testSubject, []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !verified.Load() {
+		t.Fatal("request was not signed")
+	}
+}
+
+func TestNatsCallerDefaults(t *testing.T) {
+	caller := NewNatsCaller(nil, nil, NatsCallerConfig{}).(*natsCaller)
+
+	if caller.config.BreakerThreshold != DefaultBreakerThreshold ||
+		caller.config.BreakerCooldown != DefaultBreakerCooldown ||
+		caller.config.RetryBackoff != DefaultRetryBackoff {
+		t.Fatalf("got %+v, want the defaults", caller.config)
+	}
+}
+
+func TestNatsCallerBackoff(t *testing.T) {
+	caller := &natsCaller{config: NatsCallerConfig{RetryBackoff: 100 * time.Millisecond}}
+
+	for attempt := 1; attempt <= 4; attempt++ {
+		base := 100 * time.Millisecond << (attempt - 1)
+		seen := map[time.Duration]bool{}
+		for range 50 {
+			d := caller.backoff(attempt)
+			if d < base/2 || d > base {
+				t.Fatalf("attempt %d waited %v, want within [%v, %v]", attempt, d, base/2, base)
+			}
+			seen[d] = true
+		}
+		if len(seen) < 2 {
+			t.Fatalf("attempt %d waited the same %v every time, want jitter", attempt, caller.backoff(attempt))
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/afteracademy/goserve/v2/redis"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// releases the lock only if it is still held by the caller
+var unlockScript = goredis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0
+`)
+
+type ReadThroughConfig struct {
+	// TTL is how long a loaded value is served as fresh
+	TTL time.Duration
+	// StaleTTL is how long an expired value is still served while it is
+	// reloaded in the background, 0 disables stale-while-revalidate
+	StaleTTL time.Duration
+	// NegativeTTL is how long a not found result is remembered, 0 disables
+	// negative caching
+	NegativeTTL time.Duration
+	// LockTTL bounds the rebuild of a key by one instance
+	LockTTL time.Duration
+	// LockWait is how long to wait for the rebuild of another instance before
+	// loading the value anyway
+	LockWait time.Duration
+}
+
+type LoadFunc[T any] func(ctx context.Context) (T, error)
+
+// ReadThrough is a cache-aside on top of redis.Cache that protects the loader
+// from stampedes. Concurrent misses for a key are coalesced in the process,
+// and a redis lock lets only one instance rebuild the key at a time.
+type ReadThrough[T any] interface {
+	Get(ctx context.Context, key string, load LoadFunc[T]) (T, error)
+}
+
+type readThroughEntry[T any] struct {
+	Value      T         `json:"value"`
+	Missing    string    `json:"missing,omitempty"`
+	FreshUntil time.Time `json:"freshUntil"`
+}
+
+type readThrough[T any] struct {
+	cache  redis.Cache[readThroughEntry[T]]
+	store  redis.Store
+	group  singleflight.Group
+	config ReadThroughConfig
+}
+
+func NewReadThrough[T any](store redis.Store, config ReadThroughConfig) ReadThrough[T] {
+	return &readThrough[T]{
+		cache:  redis.NewCache[readThroughEntry[T]](store),
+		store:  store,
+		config: config,
+	}
+}
+
+func (c *readThrough[T]) Get(ctx context.Context, key string, load LoadFunc[T]) (T, error) {
+	entry, err := c.cache.GetJSON(key)
+	if err == nil {
+		if time.Now().After(entry.FreshUntil) {
+			go c.group.Do(key+"_refresh", func() (any, error) {
+				return c.rebuild(context.Background(), key, load, false)
+			})
+		}
+		return c.result(entry)
+	}
+
+	// the rebuild is shared by every caller waiting on the key, so it must not
+	// be cancelled by the first one of them going away
+	loadCtx := context.WithoutCancel(ctx)
+	ch := c.group.DoChan(key, func() (any, error) {
+		return c.rebuild(loadCtx, key, load, true)
+	})
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		return c.result(res.Val.(*readThroughEntry[T]))
+	}
+}
+
+func (c *readThrough[T]) result(entry *readThroughEntry[T]) (T, error) {
+	if entry.Missing != "" {
+		var zero T
+		return zero, network.NewNotFoundError(entry.Missing, nil)
+	}
+	return entry.Value, nil
+}
+
+// rebuild loads the value under the lock of the key. When another instance
+// holds the lock a waiting caller polls for its result, while a background
+// refresh simply leaves the work to it.
+func (c *readThrough[T]) rebuild(ctx context.Context, key string, load LoadFunc[T], wait bool) (*readThroughEntry[T], error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.LockTTL)
+	defer cancel()
+
+	token, locked := c.lock(ctx, key)
+	if locked {
+		defer c.unlock(key, token)
+		// the previous holder may have stored the value just now
+		if entry, err := c.cache.GetJSON(key); err == nil && time.Now().Before(entry.FreshUntil) {
+			return entry, nil
+		}
+	} else {
+		if !wait {
+			return nil, nil
+		}
+		if entry, ok := c.waitFor(ctx, key); ok {
+			return entry, nil
+		}
+	}
+
+	value, err := load(ctx)
+	if err != nil {
+		var apiError network.ApiError
+		if c.config.NegativeTTL > 0 && IsNotFound(err) && errors.As(err, &apiError) {
+			entry := &readThroughEntry[T]{
+				Missing:    apiError.GetMessage(),
+				FreshUntil: time.Now().Add(c.config.NegativeTTL),
+			}
+			c.cache.SetJSON(key, entry, c.config.NegativeTTL)
+			return entry, nil
+		}
+		return nil, err
+	}
+
+	entry := &readThroughEntry[T]{
+		Value:      value,
+		FreshUntil: time.Now().Add(c.config.TTL),
+	}
+	c.cache.SetJSON(key, entry, c.config.TTL+c.config.StaleTTL)
+	return entry, nil
+}
+
+func (c *readThrough[T]) waitFor(ctx context.Context, key string) (*readThroughEntry[T], bool) {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	timeout := time.After(c.config.LockWait)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-timeout:
+			return nil, false
+		case <-ticker.C:
+			if entry, err := c.cache.GetJSON(key); err == nil {
+				return entry, true
+			}
+		}
+	}
+}
+
+func (c *readThrough[T]) lock(ctx context.Context, key string) (string, bool) {
+	token := uuid.NewString()
+	ok, err := c.store.GetInstance().SetNX(ctx, key+"_lock", token, c.config.LockTTL).Result()
+	if err != nil {
+		// without redis there is nothing to coordinate, let this instance load
+		return "", true
+	}
+	return token, ok
+}
+
+func (c *readThrough[T]) unlock(key string, token string) {
+	if token == "" {
+		return
+	}
+	unlockScript.Run(context.Background(), c.store.GetInstance(), []string{key + "_lock"}, token)
+}
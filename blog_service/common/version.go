@@ -0,0 +1,32 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/afteracademy/goserve/v2/network"
+	"github.com/gin-gonic/gin"
+)
+
+// ReqVersion returns the version of the resource that the client has last
+// seen, from the If-Match header, or else from the body.
+func ReqVersion(ctx *gin.Context, body *int64) (int64, error) {
+	if match := ctx.GetHeader("If-Match"); match != "" {
+		tag := strings.Trim(strings.TrimPrefix(match, "W/"), `"`)
+		version, err := strconv.ParseInt(tag, 10, 64)
+		if err != nil || version < 1 {
+			return 0, network.NewBadRequestError("If-Match must be a version", err)
+		}
+		return version, nil
+	}
+	if body != nil {
+		return *body, nil
+	}
+	return 0, NewPreconditionRequiredError("version is required in If-Match or in the body", nil)
+}
+
+// SetVersion sends the version of the resource as its ETag, to be given back
+// in If-Match.
+func SetVersion(ctx *gin.Context, version int64) {
+	ctx.Header("ETag", `"`+strconv.FormatInt(version, 10)+`"`)
+}
@@ -8,9 +8,11 @@ import (
 
 type Env struct {
 	// server
-	GoMode     string `mapstructure:"GO_MODE"`
-	ServerHost string `mapstructure:"SERVER_HOST"`
-	ServerPort uint16 `mapstructure:"SERVER_PORT"`
+	GoMode                  string `mapstructure:"GO_MODE"`
+	ServerHost              string `mapstructure:"SERVER_HOST"`
+	ServerPort              uint16 `mapstructure:"SERVER_PORT"`
+	ServerRequestTimeoutSec uint16 `mapstructure:"SERVER_REQUEST_TIMEOUT_SEC"`
+	ServerTrustedProxies    string `mapstructure:"SERVER_TRUSTED_PROXIES"`
 	// database
 	DBHost         string `mapstructure:"DB_HOST"`
 	DBName         string `mapstructure:"DB_NAME"`
@@ -30,6 +32,29 @@ type Env struct {
 	NatsServiceName    string `mapstructure:"NATS_SERVICE_NAME"`
 	NatsServiceVersion string `mapstructure:"NATS_SERVICE_VERSION"`
 	NatsTimeoutSec     uint16 `mapstructure:"NATS_TIMEOUT_SEC"`
+	NatsServiceSecret  string `mapstructure:"NATS_SERVICE_SECRET"`
+	// nats resilience
+	NatsRetryBackoffMs     uint16 `mapstructure:"NATS_RETRY_BACKOFF_MS"`
+	NatsBreakerThreshold   uint16 `mapstructure:"NATS_BREAKER_THRESHOLD"`
+	NatsBreakerCooldownSec uint16 `mapstructure:"NATS_BREAKER_COOLDOWN_SEC"`
+	// events
+	EventRelayIntervalSec uint16 `mapstructure:"EVENT_RELAY_INTERVAL_SEC"`
+	EventLeaseSec         uint16 `mapstructure:"EVENT_LEASE_SEC"`
+	EventFlushTimeoutSec  uint16 `mapstructure:"EVENT_FLUSH_TIMEOUT_SEC"`
+	// scheduled publishing
+	SchedulerIntervalSec uint16 `mapstructure:"SCHEDULER_INTERVAL_SEC"`
+	SchedulerLeaseSec    uint16 `mapstructure:"SCHEDULER_LEASE_SEC"`
+	// blog score
+	ScoreIntervalSec   uint16 `mapstructure:"SCORE_INTERVAL_SEC"`
+	ScoreHalfLifeHours uint16 `mapstructure:"SCORE_HALF_LIFE_HOURS"`
+	// views
+	ViewDedupWindowMin   uint16 `mapstructure:"VIEW_DEDUP_WINDOW_MIN"`
+	ViewFlushIntervalSec uint16 `mapstructure:"VIEW_FLUSH_INTERVAL_SEC"`
+	TrendingWindows      string `mapstructure:"TRENDING_WINDOWS"`
+	// syndication
+	PublicURL              string `mapstructure:"PUBLIC_URL"`
+	SyndicationItems       uint16 `mapstructure:"SYNDICATION_ITEMS"`
+	SyndicationCacheTTLSec uint16 `mapstructure:"SYNDICATION_CACHE_TTL_SEC"`
 }
 
 func NewEnv(filename string, override bool) *Env {
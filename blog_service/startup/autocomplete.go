@@ -0,0 +1,30 @@
+package startup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/afteracademy/gomicro/blog-service/api/autocomplete"
+	"github.com/afteracademy/gomicro/blog-service/config"
+)
+
+// RebuildAutocomplete recreates the autocomplete index from the published blogs
+// in the database, e.g. after the index was lost or has drifted.
+func RebuildAutocomplete() {
+	env := config.NewEnv(".env", true)
+	context := context.Background()
+
+	db := connectDatabase(context, env)
+	defer db.Disconnect()
+
+	store := connectStore(context, env)
+	defer store.Disconnect()
+
+	count, err := autocomplete.NewService(db, store).Rebuild(context)
+	if err != nil {
+		fmt.Println("autocomplete rebuild failed: " + err.Error())
+		return
+	}
+
+	fmt.Printf("autocomplete index rebuilt with %d blogs\n", count)
+}
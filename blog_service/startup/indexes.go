@@ -2,9 +2,33 @@ package startup
 
 import (
 	blog "github.com/afteracademy/gomicro/blog-service/api/blog/model"
+	bookmark "github.com/afteracademy/gomicro/blog-service/api/bookmark/model"
+	comment "github.com/afteracademy/gomicro/blog-service/api/comment/model"
+	event "github.com/afteracademy/gomicro/blog-service/api/event/model"
+	follow "github.com/afteracademy/gomicro/blog-service/api/follow/model"
+	reaction "github.com/afteracademy/gomicro/blog-service/api/reaction/model"
+	review "github.com/afteracademy/gomicro/blog-service/api/review/model"
+	revision "github.com/afteracademy/gomicro/blog-service/api/revision/model"
+	schedule "github.com/afteracademy/gomicro/blog-service/api/schedule/model"
+	sitemap "github.com/afteracademy/gomicro/blog-service/api/sitemap/model"
+	tag "github.com/afteracademy/gomicro/blog-service/api/tag/model"
+	workflow "github.com/afteracademy/gomicro/blog-service/api/workflow/model"
 	"github.com/afteracademy/goserve/v2/mongo"
 )
 
 func EnsureDbIndexes(db mongo.Database) {
 	go mongo.Document[blog.Blog](&blog.Blog{}).EnsureIndexes(db)
+	go mongo.Document[blog.SlugHistory](&blog.SlugHistory{}).EnsureIndexes(db)
+	go mongo.Document[event.Outbox](&event.Outbox{}).EnsureIndexes(db)
+	go mongo.Document[revision.Revision](&revision.Revision{}).EnsureIndexes(db)
+	go mongo.Document[review.Review](&review.Review{}).EnsureIndexes(db)
+	go mongo.Document[workflow.Transition](&workflow.Transition{}).EnsureIndexes(db)
+	go mongo.Document[schedule.Schedule](&schedule.Schedule{}).EnsureIndexes(db)
+	go mongo.Document[comment.Comment](&comment.Comment{}).EnsureIndexes(db)
+	go mongo.Document[reaction.Reaction](&reaction.Reaction{}).EnsureIndexes(db)
+	go mongo.Document[bookmark.Bookmark](&bookmark.Bookmark{}).EnsureIndexes(db)
+	go mongo.Document[bookmark.ReadingList](&bookmark.ReadingList{}).EnsureIndexes(db)
+	go mongo.Document[follow.Follow](&follow.Follow{}).EnsureIndexes(db)
+	go mongo.Document[tag.Tag](&tag.Tag{}).EnsureIndexes(db)
+	go mongo.Document[sitemap.Sitemap](&sitemap.Sitemap{}).EnsureIndexes(db)
 }
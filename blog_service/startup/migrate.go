@@ -0,0 +1,27 @@
+package startup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/afteracademy/gomicro/blog-service/api/workflow"
+	"github.com/afteracademy/gomicro/blog-service/config"
+)
+
+// MigrateBlogState moves the blogs stored before the workflow state existed
+// onto it. It has to run once before the service with the state is started.
+func MigrateBlogState() {
+	env := config.NewEnv(".env", true)
+	context := context.Background()
+
+	db := connectDatabase(context, env)
+	defer db.Disconnect()
+
+	count, err := workflow.NewService(db).Migrate(context)
+	if err != nil {
+		fmt.Println("blog state migration failed: " + err.Error())
+		return
+	}
+
+	fmt.Printf("blog state migrated for %d blogs\n", count)
+}
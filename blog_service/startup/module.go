@@ -2,14 +2,33 @@ package startup
 
 import (
 	"context"
+	"log/slog"
+	"time"
 
 	"github.com/afteracademy/gomicro/blog-service/api/auth"
 	authMW "github.com/afteracademy/gomicro/blog-service/api/auth/middleware"
 	"github.com/afteracademy/gomicro/blog-service/api/author"
+	"github.com/afteracademy/gomicro/blog-service/api/autocomplete"
 	"github.com/afteracademy/gomicro/blog-service/api/blog"
 	"github.com/afteracademy/gomicro/blog-service/api/blogs"
+	"github.com/afteracademy/gomicro/blog-service/api/bookmark"
+	"github.com/afteracademy/gomicro/blog-service/api/cache"
+	"github.com/afteracademy/gomicro/blog-service/api/comment"
 	"github.com/afteracademy/gomicro/blog-service/api/editor"
+	"github.com/afteracademy/gomicro/blog-service/api/event"
+	"github.com/afteracademy/gomicro/blog-service/api/follow"
 	"github.com/afteracademy/gomicro/blog-service/api/health"
+	"github.com/afteracademy/gomicro/blog-service/api/reaction"
+	"github.com/afteracademy/gomicro/blog-service/api/review"
+	"github.com/afteracademy/gomicro/blog-service/api/revision"
+	"github.com/afteracademy/gomicro/blog-service/api/schedule"
+	"github.com/afteracademy/gomicro/blog-service/api/score"
+	"github.com/afteracademy/gomicro/blog-service/api/sitemap"
+	"github.com/afteracademy/gomicro/blog-service/api/syndication"
+	"github.com/afteracademy/gomicro/blog-service/api/tag"
+	"github.com/afteracademy/gomicro/blog-service/api/view"
+	"github.com/afteracademy/gomicro/blog-service/api/workflow"
+	"github.com/afteracademy/gomicro/blog-service/common"
 	"github.com/afteracademy/gomicro/blog-service/config"
 	"github.com/afteracademy/goserve/v2/micro"
 	coreMW "github.com/afteracademy/goserve/v2/middleware"
@@ -21,14 +40,34 @@ import (
 type Module micro.Module[module]
 
 type module struct {
-	Context       context.Context
-	Env           *config.Env
-	DB            mongo.Database
-	Store         redis.Store
-	NatsClient    micro.NatsClient
-	AuthService   auth.Service
-	BlogService   blog.Service
-	HealthService health.Service
+	Context             context.Context
+	Env                 *config.Env
+	DB                  mongo.Database
+	Store               redis.Store
+	NatsClient          micro.NatsClient
+	NatsCaller          common.NatsCaller
+	Logger              *slog.Logger
+	AuthService         auth.Service
+	BlogService         blog.Service
+	BlogsService        blogs.Service
+	EventService        event.Service
+	CacheService        cache.Service
+	AutocompleteService autocomplete.Service
+	RevisionService     revision.Service
+	ReviewService       review.Service
+	WorkflowService     workflow.Service
+	ScheduleService     schedule.Service
+	EditorService       editor.Service
+	CommentService      comment.Service
+	ReactionService     reaction.Service
+	ScoreService        score.Service
+	ViewService         view.Service
+	BookmarkService     bookmark.Service
+	FollowService       follow.Service
+	TagService          tag.Service
+	SyndicationService  syndication.Service
+	SitemapService      sitemap.Service
+	HealthService       health.Service
 }
 
 func (m *module) GetInstance() *module {
@@ -39,15 +78,24 @@ func (m *module) Controllers() []micro.Controller {
 	return []micro.Controller{
 		health.NewController(m.HealthService),
 		blog.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.BlogService),
-		blogs.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), blogs.NewService(m.DB, m.Store)),
-		author.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), author.NewService(m.DB, m.BlogService)),
-		editor.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), editor.NewService(m.DB, m.AuthService)),
+		blogs.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.BlogsService),
+		autocomplete.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.AutocompleteService),
+		author.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), author.NewService(m.DB, m.BlogService, m.EventService, m.CacheService, m.AutocompleteService, m.RevisionService, m.ReviewService, m.WorkflowService, m.TagService, m.BlogsService, m.Logger)),
+		editor.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.EditorService),
+		comment.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.CommentService),
+		reaction.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.ReactionService),
+		bookmark.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.BookmarkService),
+		follow.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.FollowService),
+		tag.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.TagService),
+		syndication.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.SyndicationService),
+		sitemap.NewController(m.AuthenticationProvider(), m.AuthorizationProvider(), m.SitemapService, m.Logger),
 	}
 }
 
 func (m *module) RootMiddlewares() []network.RootMiddleware {
 	return []network.RootMiddleware{
 		coreMW.NewErrorCatcher(), // NOTE: this should be the first handler to be mounted
+		common.NewRequestDeadline(time.Duration(m.Env.ServerRequestTimeoutSec) * time.Second),
 		coreMW.NewNotFound(),
 	}
 }
@@ -60,19 +108,102 @@ func (m *module) AuthorizationProvider() network.AuthorizationProvider {
 	return authMW.NewAuthorizationProvider(m.AuthService)
 }
 
-func NewModule(context context.Context, env *config.Env, db mongo.Database, store redis.Store, natsClient micro.NatsClient) Module {
-	authService := auth.NewService(natsClient)
-	blogService := blog.NewService(db, store, authService)
-	healthService := health.NewService()
+func NewModule(context context.Context, env *config.Env, db mongo.Database, store redis.Store, natsClient micro.NatsClient, logger *slog.Logger) Module {
+	identity := common.NewServiceIdentity(env.NatsServiceName, env.NatsServiceSecret)
+	natsCaller := common.NewNatsCaller(natsClient, identity, common.NatsCallerConfig{
+		Timeout:          time.Duration(env.NatsTimeoutSec) * time.Second,
+		RetryBackoff:     time.Duration(env.NatsRetryBackoffMs) * time.Millisecond,
+		BreakerThreshold: int(env.NatsBreakerThreshold),
+		BreakerCooldown:  time.Duration(env.NatsBreakerCooldownSec) * time.Second,
+	})
+	trendingWindows, err := view.ParseWindows(env.TrendingWindows)
+	if err != nil {
+		panic(err)
+	}
+	viewService := view.NewService(db, store, logger, view.Config{
+		DedupWindow:   time.Duration(env.ViewDedupWindowMin) * time.Minute,
+		FlushInterval: time.Duration(env.ViewFlushIntervalSec) * time.Second,
+		Windows:       trendingWindows,
+	})
+	authService := auth.NewService(natsCaller)
+	blogService := blog.NewService(db, store, authService, viewService, logger)
+	autocompleteService := autocomplete.NewService(db, store)
+	revisionService := revision.NewService(db)
+	tagService := tag.NewService(db, autocompleteService, revisionService, logger)
+	followService := follow.NewService(db, store, authService, tagService)
+	sitemapService := sitemap.NewService(db, sitemap.Config{
+		PublicURL: env.PublicURL,
+	})
+	blogsService := blogs.NewService(db, store, viewService, followService, tagService, sitemapService)
+	syndicationService := syndication.NewService(store, blogsService, authService, logger, syndication.Config{
+		PublicURL: env.PublicURL,
+		Items:     int64(env.SyndicationItems),
+		CacheTTL:  time.Duration(env.SyndicationCacheTTLSec) * time.Second,
+	})
+	cacheService := cache.NewService(natsClient, identity, blogService, blogsService, logger)
+	reviewService := review.NewService(db)
+	workflowService := workflow.NewService(db)
+	scheduleService := schedule.NewService(db, logger, schedule.Config{
+		Interval: time.Duration(env.SchedulerIntervalSec) * time.Second,
+		Lease:    time.Duration(env.SchedulerLeaseSec) * time.Second,
+	})
+	eventService := event.NewService(db, natsClient, identity, logger, event.Config{
+		RelayInterval: time.Duration(env.EventRelayIntervalSec) * time.Second,
+		Lease:         time.Duration(env.EventLeaseSec) * time.Second,
+		FlushTimeout:  time.Duration(env.EventFlushTimeoutSec) * time.Second,
+	})
+	editorService := editor.NewService(
+		db,
+		authService,
+		eventService,
+		cacheService,
+		autocompleteService,
+		revisionService,
+		reviewService,
+		workflowService,
+		scheduleService,
+		tagService,
+		blogsService,
+		logger,
+	)
+	tagService.OnBlogsChanged(editorService.RefreshBlog)
+	commentService := comment.NewService(db, authService, blogService, logger)
+	reactionService := reaction.NewService(db, blogService, logger)
+	bookmarkService := bookmark.NewService(db, blogService)
+	scoreService := score.NewService(db, logger, score.Config{
+		Interval: time.Duration(env.ScoreIntervalSec) * time.Second,
+		HalfLife: time.Duration(env.ScoreHalfLifeHours) * time.Hour,
+	})
+	healthService := health.NewService(natsCaller)
 
 	return &module{
-		Context:       context,
-		Env:           env,
-		DB:            db,
-		Store:         store,
-		NatsClient:    natsClient,
-		AuthService:   authService,
-		BlogService:   blogService,
-		HealthService: healthService,
+		Context:             context,
+		Env:                 env,
+		DB:                  db,
+		Store:               store,
+		NatsClient:          natsClient,
+		NatsCaller:          natsCaller,
+		Logger:              logger,
+		AuthService:         authService,
+		BlogService:         blogService,
+		BlogsService:        blogsService,
+		EventService:        eventService,
+		CacheService:        cacheService,
+		AutocompleteService: autocompleteService,
+		RevisionService:     revisionService,
+		ReviewService:       reviewService,
+		WorkflowService:     workflowService,
+		ScheduleService:     scheduleService,
+		EditorService:       editorService,
+		CommentService:      commentService,
+		ReactionService:     reactionService,
+		ScoreService:        scoreService,
+		ViewService:         viewService,
+		BookmarkService:     bookmarkService,
+		FollowService:       followService,
+		TagService:          tagService,
+		SyndicationService:  syndicationService,
+		SitemapService:      sitemapService,
+		HealthService:       healthService,
 	}
 }
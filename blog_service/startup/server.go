@@ -2,6 +2,9 @@ package startup
 
 import (
 	"context"
+	"log/slog"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/afteracademy/gomicro/blog-service/config"
@@ -24,33 +27,13 @@ func Server() {
 func create(env *config.Env) (micro.Router, Module, Shutdown) {
 	context := context.Background()
 
-	dbConfig := mongo.DbConfig{
-		User:        env.DBUser,
-		Pwd:         env.DBUserPwd,
-		Host:        env.DBHost,
-		Port:        env.DBPort,
-		Name:        env.DBName,
-		MinPoolSize: env.DBMinPoolSize,
-		MaxPoolSize: env.DBMaxPoolSize,
-		Timeout:     time.Duration(env.DBQueryTimeout) * time.Second,
-	}
-
-	db := mongo.NewDatabase(context, dbConfig)
-	db.Connect()
+	db := connectDatabase(context, env)
 
 	if env.GoMode != gin.TestMode {
 		EnsureDbIndexes(db)
 	}
 
-	redisConfig := redis.Config{
-		Host: env.RedisHost,
-		Port: env.RedisPort,
-		Pwd:  env.RedisPwd,
-		DB:   env.RedisDB,
-	}
-
-	store := redis.NewStore(context, &redisConfig)
-	store.Connect()
+	store := connectStore(context, env)
 
 	natsConfig := micro.Config{
 		NatsUrl:            env.NatsUrl,
@@ -61,14 +44,33 @@ func create(env *config.Env) (micro.Router, Module, Shutdown) {
 
 	natsClient := micro.NewNatsClient(&natsConfig)
 
-	module := NewModule(context, env, db, store, natsClient)
+	// the failures of the background work and of the best effort steps of a
+	// request, which have no response to report them in
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	module := NewModule(context, env, db, store, natsClient, logger)
+	module.GetInstance().EventService.StartRelay()
+	module.GetInstance().ScheduleService.StartScheduler(module.GetInstance().EditorService.RunSchedule)
+	module.GetInstance().ScoreService.StartScorer()
+	module.GetInstance().ViewService.StartFlusher()
+	if err := module.GetInstance().CacheService.Subscribe(); err != nil {
+		panic(err)
+	}
 
 	router := micro.NewRouter(env.GoMode, natsClient)
+	if err := router.GetEngine().SetTrustedProxies(trustedProxies(env.ServerTrustedProxies)); err != nil {
+		panic(err)
+	}
 	router.RegisterValidationParsers(network.CustomTagNameFunc())
 	router.LoadRootMiddlewares(module.RootMiddlewares())
 	router.LoadControllers(module.Controllers())
 
 	shutdown := func() {
+		module.GetInstance().ViewService.StopFlusher()
+		module.GetInstance().ScoreService.StopScorer()
+		module.GetInstance().ScheduleService.StopScheduler()
+		module.GetInstance().EventService.StopRelay()
+		module.GetInstance().CacheService.Unsubscribe()
 		db.Disconnect()
 		store.Disconnect()
 		natsClient.Disconnect()
@@ -76,3 +78,45 @@ func create(env *config.Env) (micro.Router, Module, Shutdown) {
 
 	return router, module, shutdown
 }
+
+// trustedProxies parses the comma separated proxies, an empty value trusts
+// none of them.
+func trustedProxies(value string) []string {
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+func connectDatabase(context context.Context, env *config.Env) mongo.Database {
+	dbConfig := mongo.DbConfig{
+		User:        env.DBUser,
+		Pwd:         env.DBUserPwd,
+		Host:        env.DBHost,
+		Port:        env.DBPort,
+		Name:        env.DBName,
+		MinPoolSize: env.DBMinPoolSize,
+		MaxPoolSize: env.DBMaxPoolSize,
+		Timeout:     time.Duration(env.DBQueryTimeout) * time.Second,
+	}
+
+	db := mongo.NewDatabase(context, dbConfig)
+	db.Connect()
+	return db
+}
+
+func connectStore(context context.Context, env *config.Env) redis.Store {
+	redisConfig := redis.Config{
+		Host: env.RedisHost,
+		Port: env.RedisPort,
+		Pwd:  env.RedisPwd,
+		DB:   env.RedisDB,
+	}
+
+	store := redis.NewStore(context, &redisConfig)
+	store.Connect()
+	return store
+}
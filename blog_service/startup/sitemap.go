@@ -0,0 +1,27 @@
+package startup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/afteracademy/gomicro/blog-service/api/sitemap"
+	"github.com/afteracademy/gomicro/blog-service/config"
+)
+
+// RebuildSitemaps regenerates all the sitemaps from the published blogs, e.g.
+// when a refresh after a publication change has failed.
+func RebuildSitemaps() {
+	env := config.NewEnv(".env", true)
+	context := context.Background()
+
+	db := connectDatabase(context, env)
+	defer db.Disconnect()
+
+	count, err := sitemap.NewService(db, sitemap.Config{PublicURL: env.PublicURL}).Rebuild(context)
+	if err != nil {
+		fmt.Println("sitemap rebuild failed: " + err.Error())
+		return
+	}
+
+	fmt.Printf("sitemaps rebuilt into %d files\n", count)
+}
@@ -0,0 +1,36 @@
+package startup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/afteracademy/gomicro/blog-service/api/autocomplete"
+	"github.com/afteracademy/gomicro/blog-service/api/revision"
+	"github.com/afteracademy/gomicro/blog-service/api/tag"
+	"github.com/afteracademy/gomicro/blog-service/config"
+)
+
+// RecountTags fills the tag registry from the published blogs, e.g. for the
+// blogs published before the registry existed.
+func RecountTags() {
+	env := config.NewEnv(".env", true)
+	context := context.Background()
+
+	db := connectDatabase(context, env)
+	defer db.Disconnect()
+
+	store := connectStore(context, env)
+	defer store.Disconnect()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	tagService := tag.NewService(db, autocomplete.NewService(db, store), revision.NewService(db), logger)
+	count, err := tagService.RecountAll(context)
+	if err != nil {
+		fmt.Println("tag recount failed: " + err.Error())
+		return
+	}
+
+	fmt.Printf("tag registry recounted with %d tags\n", count)
+}
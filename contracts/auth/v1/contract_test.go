@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// The fixtures are the wire format of v1. A failure here means a breaking
+// change, which belongs to a new version of the contracts instead.
+func TestContractsWireFormat(t *testing.T) {
+	id := uuid.MustParse("0b6e1c4e-8f5a-4c1d-9a37-2f1f0d3c5e7a")
+	other := uuid.MustParse("6f2d0c1b-3a4e-4b5c-8d9e-0a1b2c3d4e5f")
+	pic := "https://example.com/pic.png"
+
+	tests := []struct {
+		name    string
+		value   any
+		fixture string
+	}{
+		{
+			name:    "text",
+			value:   NewText("token"),
+			fixture: `{"value":"token"}`,
+		},
+		{
+			name:    "user",
+			value:   &User{ID: id, Name: "Jane", Email: "jane@example.com", ProfilePicURL: &pic},
+			fixture: `{"id":"0b6e1c4e-8f5a-4c1d-9a37-2f1f0d3c5e7a","name":"Jane","email":"jane@example.com","profilePicUrl":"https://example.com/pic.png"}`,
+		},
+		{
+			name:    "user without picture",
+			value:   &User{ID: id, Name: "Jane", Email: "jane@example.com"},
+			fixture: `{"id":"0b6e1c4e-8f5a-4c1d-9a37-2f1f0d3c5e7a","name":"Jane","email":"jane@example.com"}`,
+		},
+		{
+			name:    "user role",
+			value:   NewUserRole(&User{ID: id, Name: "Jane", Email: "jane@example.com"}, string(RoleCodeAuthor), string(RoleCodeEditor)),
+			fixture: `{"user":{"id":"0b6e1c4e-8f5a-4c1d-9a37-2f1f0d3c5e7a","name":"Jane","email":"jane@example.com"},"roles":["AUTHOR","EDITOR"]}`,
+		},
+		{
+			name:    "user ids",
+			value:   NewUserIds([]uuid.UUID{id, other}),
+			fixture: `{"ids":["0b6e1c4e-8f5a-4c1d-9a37-2f1f0d3c5e7a","6f2d0c1b-3a4e-4b5c-8d9e-0a1b2c3d4e5f"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(data) != tt.fixture {
+				t.Errorf("marshal:\n got %s\nwant %s", data, tt.fixture)
+			}
+
+			decoded := reflect.New(reflect.TypeOf(tt.value).Elem()).Interface()
+			if err = json.Unmarshal([]byte(tt.fixture), decoded); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tt.value) {
+				t.Errorf("unmarshal:\n got %+v\nwant %+v", decoded, tt.value)
+			}
+		})
+	}
+}
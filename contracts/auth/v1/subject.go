@@ -0,0 +1,21 @@
+package v1
+
+// Version of the auth message contracts in this package. A breaking change to
+// any message must go into a new package (v2) instead of editing this one.
+const Version = "v1"
+
+// endpoints mounted by auth_service, relative to its controller groups
+const (
+	NATS_ENDPOINT_AUTH         = "authentication"
+	NATS_ENDPOINT_AUTHZ        = "authorization"
+	NATS_ENDPOINT_USERPROFILE  = "user"
+	NATS_ENDPOINT_USERPROFILES = "users"
+)
+
+// fully qualified subjects used by the callers of auth_service
+const (
+	NATS_TOPIC_AUTH         = "auth." + NATS_ENDPOINT_AUTH
+	NATS_TOPIC_AUTHZ        = "auth." + NATS_ENDPOINT_AUTHZ
+	NATS_TOPIC_USERPROFILE  = "auth.profile." + NATS_ENDPOINT_USERPROFILE
+	NATS_TOPIC_USERPROFILES = "auth.profile." + NATS_ENDPOINT_USERPROFILES
+)
@@ -0,0 +1,11 @@
+package v1
+
+type Text struct {
+	Value string `json:"value" validate:"required"`
+}
+
+func NewText(value string) *Text {
+	return &Text{
+		Value: value,
+	}
+}
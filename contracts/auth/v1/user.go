@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"github.com/google/uuid"
+)
+
+type RoleCode string
+
+const (
+	RoleCodeLearner RoleCode = "LEARNER"
+	RoleCodeAdmin   RoleCode = "ADMIN"
+	RoleCodeAuthor  RoleCode = "AUTHOR"
+	RoleCodeEditor  RoleCode = "EDITOR"
+)
+
+type User struct {
+	ID            uuid.UUID `json:"id" validate:"required,uuid"`
+	Name          string    `json:"name" validate:"required"`
+	Email         string    `json:"email" validate:"required,email"`
+	ProfilePicURL *string   `json:"profilePicUrl,omitempty" validate:"omitempty,url"`
+}
+
+// MaxUserIds bounds the ids of one UserIds request.
+const MaxUserIds = 100
+
+// UserIds asks for the public profiles of several users at once.
+type UserIds struct {
+	Ids []uuid.UUID `json:"ids" validate:"required,max=100"`
+}
+
+func NewUserIds(ids []uuid.UUID) *UserIds {
+	return &UserIds{
+		Ids: ids,
+	}
+}
+
+// Users answers UserIds, in no particular order. The users that were not
+// found are left out.
+type Users struct {
+	Users []*User `json:"users"`
+}
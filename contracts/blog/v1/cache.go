@@ -0,0 +1,8 @@
+package v1
+
+// BlogCacheInvalidation asks every blog_service instance to drop its cached
+// copies of a blog, Slugs also lists the slugs the blog had before.
+type BlogCacheInvalidation struct {
+	BlogID string   `json:"blogId" validate:"required"`
+	Slugs  []string `json:"slugs"`
+}
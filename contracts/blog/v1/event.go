@@ -0,0 +1,19 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BlogEvent is delivered at least once, consumers should use ID to drop the
+// duplicates.
+type BlogEvent struct {
+	ID           uuid.UUID `json:"id" validate:"required"`
+	Type         string    `json:"type" validate:"required"`
+	BlogID       string    `json:"blogId" validate:"required"`
+	Slug         string    `json:"slug" validate:"required"`
+	PreviousSlug *string   `json:"previousSlug,omitempty"`
+	Actor        uuid.UUID `json:"actor" validate:"required"`
+	OccurredAt   time.Time `json:"occurredAt" validate:"required"`
+}
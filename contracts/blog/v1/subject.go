@@ -0,0 +1,22 @@
+package v1
+
+// Version of the blog message contracts in this package. A breaking change to
+// any message must go into a new package (v2) instead of editing this one.
+const Version = "v1"
+
+// lifecycle events published by blog_service, the subject is the event type
+const (
+	NATS_EVENT_BLOG_CREATED     = "blog.created"
+	NATS_EVENT_BLOG_UPDATED     = "blog.updated"
+	NATS_EVENT_BLOG_SUBMITTED   = "blog.submitted"
+	NATS_EVENT_BLOG_WITHDRAWN   = "blog.withdrawn"
+	NATS_EVENT_BLOG_PUBLISHED   = "blog.published"
+	NATS_EVENT_BLOG_UNPUBLISHED = "blog.unpublished"
+	NATS_EVENT_BLOG_REJECTED    = "blog.rejected"
+	NATS_EVENT_BLOG_DELETED     = "blog.deleted"
+)
+
+// broadcast to every blog_service instance, not only to one of the queue group
+const (
+	NATS_BROADCAST_BLOG_CACHE_INVALIDATE = "blog.cache.invalidate"
+)
@@ -0,0 +1,31 @@
+package deadline
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// HeaderDeadline carries the absolute deadline of the caller, in unix
+// milliseconds, so that the receiving service stops working on a request the
+// caller has already given up on.
+const HeaderDeadline = "Gomicro-Deadline"
+
+func Format(ctx context.Context) (string, bool) {
+	t, ok := ctx.Deadline()
+	if !ok {
+		return "", false
+	}
+	return strconv.FormatInt(t.UnixMilli(), 10), true
+}
+
+func Parse(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
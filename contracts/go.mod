@@ -0,0 +1,5 @@
+module github.com/afteracademy/gomicro/contracts
+
+go 1.25.6
+
+require github.com/google/uuid v1.6.0